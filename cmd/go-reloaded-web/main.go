@@ -6,7 +6,6 @@ package main
 import (
 	"context"
 	"fmt"
-	"html"
 	"html/template"
 	"log"
 	"net"
@@ -22,6 +21,7 @@ import (
 	"time"
 
 	"go-reloaded/internal/processor"
+	"go-reloaded/internal/validator"
 )
 
 // Configuration constants
@@ -36,15 +36,21 @@ const (
 
 // PageData represents the template data structure
 type PageData struct {
-	Input  string `json:"input"`
-	Output string `json:"output"`
-	Error  string `json:"error"`
+	Input       string   `json:"input"`
+	Output      string   `json:"output"`
+	Error       string   `json:"error"`
+	Diagnostics []string `json:"diagnostics"`
+	Format      string   `json:"format"`
 }
 
 // Server state management
 var (
 	activeSessions = make(map[string]bool)
 	sessionMutex   sync.RWMutex
+
+	// The processor keeps its options and correction trackers in package state, so
+	// requests take turns using it
+	processMutex sync.Mutex
 )
 
 const htmlTemplate = `<!DOCTYPE html>
@@ -334,6 +340,12 @@ textarea:focus {
   transform: translateY(-2px);
 }
 
+.format-select {
+  border-radius: var(--radius);
+  padding: 8px 12px;
+  font-size: 0.9rem;
+}
+
 .clear-btn {
   background: #b85450;
   color: #fff;
@@ -391,6 +403,25 @@ textarea:focus {
   }
 }
 
+.diagnostics-message {
+  margin-top: 12px;
+  padding: 12px;
+  background: #fdf6e3;
+  border: 1px solid #eedc9a;
+  border-radius: var(--radius);
+  color: #7a5c00;
+  font-size: 0.9rem;
+  white-space: pre-wrap;
+}
+
+@media (prefers-color-scheme: dark) {
+  .diagnostics-message {
+    background: #3d3414;
+    border: 1px solid #5c4f1e;
+    color: #f0d98c;
+  }
+}
+
 .how-to {
   margin-top: 12px;
   padding: 12px;
@@ -557,15 +588,23 @@ footer {
 
   <section class="center">
     <form method="POST">
-      <textarea name="input" id="input" placeholder="Enter your text here...">{{.Input | html}}</textarea>
-      <textarea id="output" class="output" placeholder="Transformed output..." readonly>{{.Output | html}}</textarea>
+      <textarea name="input" id="input" placeholder="Enter your text here...">{{.Input}}</textarea>
+      <textarea id="output" class="output" placeholder="Transformed output..." readonly>{{.Output}}</textarea>
       <div class="button-row">
+        <select name="format" class="format-select" title="Input format">
+          <option value="text">Plain text</option>
+          <option value="markdown"{{if eq .Format "markdown"}} selected{{end}}>Markdown</option>
+          <option value="html"{{if eq .Format "html"}} selected{{end}}>HTML</option>
+          <option value="go"{{if eq .Format "go"}} selected{{end}}>Go comments</option>
+        </select>
         <button type="submit" class="transform-btn">Transform Text</button>
         <button type="button" class="clear-btn" onclick="return clearText(event);">Clear</button>
       </div>
     </form>
     <div class="error-message" {{if .Error}}style="display: block;"{{end}}>{{.Error}}</div>
     <div class="info-message" id="infoMessage" style="display: none;"></div>
+    {{if .Diagnostics}}<div class="diagnostics-message" id="diagnosticsMessage">Possible modifier mistakes:{{range .Diagnostics}}
+• {{.}}{{end}}</div>{{end}}
     <div class="error-dialog" id="errorDialog" style="display: none;">
       <div class="error-dialog-content">
         <h4>Issue Found</h4>
@@ -608,7 +647,7 @@ footer {
       • Single Enter: New line<br>
       • Double Enter: Transform text<br>
       • Use Transform button or double Enter<br>
-      • Only plain text is supported
+      • Choose Markdown or HTML to keep code and markup as written
     </div>
   </section>
 
@@ -1039,14 +1078,40 @@ func isPortAvailable(port int) bool {
 	return true
 }
 
-func main() {
-	// Create template with custom functions to handle HTML entities
-	funcMap := template.FuncMap{
-		"html": func(s string) template.HTML {
-			return template.HTML(s)
-		},
+// processRequest transforms the input of one request in the given format and returns the
+// output with the diagnostics for it. Requests are processed one at a time, so one user's
+// format never leaks into another user's request.
+func processRequest(input, format string, intentional bool) (string, []string) {
+	processMutex.Lock()
+	defer processMutex.Unlock()
+
+	opts := processor.GetOptions()
+	opts.Format = format
+	processor.SetOptions(opts)
+
+	// Oversized input is never parsed, even when marked as intentional
+	if err := validator.ValidateSize(input); err != nil {
+		return "ERROR: " + err.Error(), nil
 	}
-	tmpl := template.Must(template.New("index").Funcs(funcMap).Parse(htmlTemplate))
+
+	// Skip validation if user marked as intentional
+	if !intentional {
+		if err := processor.ValidateText(input); err != nil {
+			return "ERROR: " + err.Error(), nil
+		}
+	}
+
+	// Point out likely modifier mistakes alongside the result
+	var diagnostics []string
+	for _, d := range processor.Diagnose(input) {
+		diagnostics = append(diagnostics, d.String())
+	}
+	return processor.ProcessTextUnsafeWithInfo(input), diagnostics
+}
+
+func main() {
+	// The template escapes input and output, so markup and entities show exactly as typed
+	tmpl := template.Must(template.New("index").Parse(htmlTemplate))
 
 	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 		data := PageData{}
@@ -1056,19 +1121,20 @@ func main() {
 		trackSession(sessionID)
 
 		if r.Method == "POST" {
+			// The input is used exactly as typed, so entities such as &amp; are kept
 			input := r.FormValue("input")
-			// Decode HTML entities for web UI
-			input = html.UnescapeString(input)
 			intentional := r.FormValue("intentional")
+
+			// Markdown and HTML input only has its prose transformed
+			data.Format = processor.FormatText
+			if format := r.FormValue("format"); processor.IsSupportedFormat(format) {
+				data.Format = format
+			}
+
 			if input != "" {
-				// Skip validation if user marked as intentional
-				var output string
-				if intentional == "true" {
-					output = processor.ProcessTextUnsafeWithInfo(input)
-				} else {
-					output = processor.ProcessTextWithInfo(input)
-				}
-				
+				output, diagnostics := processRequest(input, data.Format, intentional == "true")
+				data.Diagnostics = diagnostics
+
 				if strings.HasPrefix(output, "ERROR:") {
 					data.Error = output[7:] // Remove 'ERROR: ' prefix
 					data.Input = input
@@ -1,34 +1,171 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"go-reloaded/internal/processor"
 	"os"
+	"strings"
 )
 
+// aliasFlag collects repeated --alias name=modifier options
+type aliasFlag map[string]string
+
+func (a aliasFlag) String() string {
+	var pairs []string
+	for name, target := range a {
+		pairs = append(pairs, name+"="+target)
+	}
+	return strings.Join(pairs, ",")
+}
+
+func (a aliasFlag) Set(value string) error {
+	name, target, ok := strings.Cut(value, "=")
+	if !ok || name == "" || target == "" {
+		return fmt.Errorf("alias must look like name=modifier, got %q", value)
+	}
+	a[name] = target
+	return nil
+}
+
 func main() {
-	if len(os.Args) == 1 {
+	locale := flag.String("locale", "en", "number formatting locale (en, de, fr, el)")
+	punctuation := flag.String("punctuation", "", "punctuation spacing and quote marks (en, fr, de, el); defaults to the locale")
+	punctRules := flag.String("punct-rules", "", "punctuation rules: any of abbreviations,ellipsis=dots|char,ellipsis-spacing=attached|spaced,interrobang=?!|!?,max-repeat=N")
+	spellBelow := flag.Int("spell-below", 0, "spell out integers below this value in prose (0 disables)")
+	strictRoman := flag.Bool("strict-roman", false, "reject non-canonical Roman numerals such as IIII")
+	wordCount := flag.String("count", "", "word counting for (up, N): any of numbers,compounds,punctuation,sentence,line")
+	autoCap := flag.Bool("auto-cap", false, "capitalize the first word of every sentence and the pronoun I")
+	typos := flag.String("typos", "", "repeated words and typos: fix to correct them, check to report them")
+	tightOps := flag.String("tight-ops", processor.DefaultTightOperators, "operators that lose the space before them, e.g. \"%&\" (all: "+processor.AllOperators+")")
+	typography := flag.String("typography", "", "smart for curly quotes, dashes and ellipses, ascii to convert them back")
+	dashSpacing := flag.String("dash-spacing", "", "spaces around smart dashes: closed or spaced (default keeps them as written)")
+	quoteStyle := flag.String("quote-style", "", "house quote style: double (\"outer 'inner'\") or single ('outer \"inner\"')")
+	format := flag.String("format", "", "input format: text, markdown, html, go, hash-comments or slash-comments (default: from the input file's extension)")
+	stringLiterals := flag.Bool("strings", false, "with --format go, also transform string literals that read as prose")
+	wrap := flag.Int("wrap", 0, "reflow paragraphs to this many columns, keeping list markers and > prefixes (0 disables)")
+	justify := flag.Bool("justify", false, "pad wrapped lines to the full --wrap width")
+	exceptionsFile := flag.String("exceptions", "", "file of words that keep their casing under (cap) and (low), e.g. gRPC")
+	aliases := aliasFlag{}
+	flag.Var(aliases, "alias", "define a modifier alias such as u=up or lc=low|cap (repeatable)")
+	flag.Usage = printUsage
+	flag.Parse()
+
+	if flag.NArg() == 0 {
 		fmt.Println("Go Reloaded - Text Transformation Tool")
 		fmt.Println("")
-		fmt.Println("Usage:")
-		fmt.Println("  CLI:  go run . <input-file> <output-file>")
-		fmt.Println("  Web:  go run ./cmd/go-reloaded-web")
+		printUsage()
+		os.Exit(1)
+	}
+
+	if flag.NArg() != 2 {
+		fmt.Println("Usage: go run . [options] <input-file> <output-file>")
+		os.Exit(1)
+	}
+
+	if !processor.IsSupportedLocale(*locale) {
+		fmt.Printf("Error: unsupported locale %q\n", *locale)
+		os.Exit(1)
+	}
+
+	policy, err := processor.ParseWordCountPolicy(*wordCount)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *typos != "" && *typos != processor.TyposFix && *typos != processor.TyposCheck {
+		fmt.Printf("Error: --typos must be fix or check, got %q\n", *typos)
+		os.Exit(1)
+	}
+
+	if *punctuation != "" && !processor.IsSupportedPunctuationProfile(*punctuation) {
+		fmt.Printf("Error: unsupported punctuation profile %q\n", *punctuation)
 		os.Exit(1)
 	}
 
-	if len(os.Args) != 3 {
-		fmt.Println("Usage: go run . <input-file> <output-file>")
+	rules, err := processor.ParsePunctuationRules(*punctRules)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
 		os.Exit(1)
 	}
 
-	inputFile := os.Args[1]
-	outputFile := os.Args[2]
+	if *typography != "" && *typography != processor.TypographySmart && *typography != processor.TypographyASCII {
+		fmt.Printf("Error: --typography must be smart or ascii, got %q\n", *typography)
+		os.Exit(1)
+	}
+	if *dashSpacing != "" && *dashSpacing != processor.DashClosed && *dashSpacing != processor.DashSpaced {
+		fmt.Printf("Error: --dash-spacing must be closed or spaced, got %q\n", *dashSpacing)
+		os.Exit(1)
+	}
+	if *quoteStyle != "" && *quoteStyle != processor.QuoteStyleDouble && *quoteStyle != processor.QuoteStyleSingle {
+		fmt.Printf("Error: --quote-style must be double or single, got %q\n", *quoteStyle)
+		os.Exit(1)
+	}
 
-	err := processor.ProcessFile(inputFile, outputFile)
+	if *format == "" {
+		*format = processor.FormatForFile(flag.Arg(0))
+	}
+	if !processor.IsSupportedFormat(*format) {
+		fmt.Printf("Error: --format must be text, markdown, html, go, hash-comments or slash-comments, got %q\n", *format)
+		os.Exit(1)
+	}
+	if *wrap < 0 {
+		fmt.Printf("Error: --wrap must be 0 or a width, got %d\n", *wrap)
+		os.Exit(1)
+	}
+
+	opts := processor.DefaultOptions()
+	opts.Locale = *locale
+	opts.PunctuationProfile = *punctuation
+	opts.PunctuationRules = rules
+	opts.SpellOutBelow = *spellBelow
+	opts.StrictRoman = *strictRoman
+	opts.Aliases = aliases
+	opts.WordCount = policy
+	opts.AutoCapitalize = *autoCap
+	opts.Typos = *typos
+	opts.TightOperators = *tightOps
+	opts.Typography = *typography
+	opts.DashSpacing = *dashSpacing
+	opts.QuoteStyle = *quoteStyle
+	opts.Format = *format
+	opts.StringLiterals = *stringLiterals
+	opts.Wrap = *wrap
+	opts.Justify = *justify
+	if *exceptionsFile != "" {
+		content, err := os.ReadFile(*exceptionsFile)
+		if err != nil {
+			fmt.Printf("Error: failed to read exceptions file: %v\n", err)
+			os.Exit(1)
+		}
+		opts.CaseExceptions = strings.Fields(string(content))
+	}
+	processor.SetOptions(opts)
+
+	inputFile := flag.Arg(0)
+	outputFile := flag.Arg(1)
+
+	diagnostics, err := processor.ProcessFile(inputFile, outputFile)
 	if err != nil {
 		fmt.Printf("Error: %v\n", err)
 		os.Exit(1)
 	}
 
+	// Report likely modifier mistakes without failing the run
+	for _, d := range diagnostics {
+		fmt.Fprintf(os.Stderr, "%s:%s\n", inputFile, d)
+	}
+
 	fmt.Printf("Successfully processed %s → %s\n", inputFile, outputFile)
 }
+
+// printUsage shows the command line syntax and available options
+func printUsage() {
+	fmt.Println("Usage:")
+	fmt.Println("  CLI:  go run . [options] <input-file> <output-file>")
+	fmt.Println("  Web:  go run ./cmd/go-reloaded-web")
+	fmt.Println("")
+	fmt.Println("Options:")
+	flag.PrintDefaults()
+}
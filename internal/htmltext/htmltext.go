@@ -0,0 +1,260 @@
+// Copyright (c) 2025 Spiros Nikoloudakis
+// Licensed under MIT License - see LICENSE file for details
+
+package htmltext
+
+import (
+	"go-reloaded/internal/layout"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Kind tells what a block of an HTML document holds
+type Kind int
+
+const (
+	Verbatim Kind = iota // markup, comments, whitespace between blocks and raw elements such as <pre>
+	Text                 // the text of one block element, with the inline markup inside it
+)
+
+// Inline is a piece of a text block: prose to transform, or an inline tag, entity or
+// code element to keep as written
+type Inline = layout.Inline
+
+// Block is a verbatim part of the document or the text of one block element
+type Block struct {
+	Kind    Kind
+	Raw     string   // exact source of the block
+	Inlines []Inline // the pieces of a Text block; their Text adds up to Raw
+}
+
+// Document holds the blocks of an HTML text in order; their Raw texts add up to Text
+type Document struct {
+	Text   string
+	Blocks []*Block
+}
+
+// blockElements end the text before them and start a new text block. Every other
+// element is inline and stays part of the text around it.
+var blockElements = map[string]bool{
+	"address": true, "article": true, "aside": true, "blockquote": true, "body": true,
+	"caption": true, "dd": true, "details": true, "dialog": true, "div": true, "dl": true, "dt": true,
+	"fieldset": true, "figcaption": true, "figure": true, "footer": true, "form": true, "h1": true,
+	"h2": true, "h3": true, "h4": true, "h5": true, "h6": true, "head": true, "header": true, "hr": true,
+	"html": true, "legend": true, "li": true, "main": true, "meta": true, "link": true, "nav": true,
+	"ol": true, "option": true, "p": true, "section": true, "summary": true, "table": true,
+	"tbody": true, "td": true, "tfoot": true, "th": true, "thead": true, "title": true, "tr": true, "ul": true,
+}
+
+// rawElements keep everything up to their end tag as written. <code> is inline, the
+// others end the text block before them.
+var rawElements = map[string]bool{"script": true, "style": true, "pre": true, "code": true, "textarea": true}
+
+// breakElements are inline but end the word before them, as <br> ends a line
+var breakElements = map[string]bool{"br": true, "hr": true, "img": true, "wbr": true}
+
+// tagNamePattern matches the start of a start or end tag and captures the element name
+var tagNamePattern = regexp.MustCompile(`^</?([A-Za-z][A-Za-z0-9:-]*)`)
+
+// entityPattern matches a named or numeric character reference such as &amp; or &#8217;
+var entityPattern = regexp.MustCompile(`^&(?:#[0-9]{1,7}|#[xX][0-9a-fA-F]{1,6}|[A-Za-z][A-Za-z0-9]{1,31});`)
+
+// parser holds the state of one Parse call
+type parser struct {
+	doc     *Document
+	inlines []Inline // the text block being collected
+	end     int      // offset just past the last piece added to it
+}
+
+// Parse splits an HTML document into verbatim markup and blocks of text.
+//
+// The tokenizer knows comments, CDATA sections, doctypes, start tags with quoted or
+// unquoted attribute values, and end tags. A < that starts none of them is text, as in
+// "a < b". Block elements such as <p> and <li> separate text blocks; inline elements such
+// as <b> and <a>, entities and <code> elements are kept inside the text as written.
+// <script>, <style>, <pre> and <textarea> elements are verbatim up to their end tag.
+func Parse(text string) *Document {
+	p := &parser{doc: &Document{Text: text}}
+	for i := 0; i < len(text); {
+		n, name, isEnd := tagAt(text, i)
+		switch {
+		case n > 0 && !isEnd && rawElements[name]:
+			n = rawElementEnd(text, i+n, name) - i
+			if name == "code" {
+				p.add(i, i+n, false)
+			} else {
+				p.flush()
+				p.verbatim(i, i+n)
+			}
+		case n > 0 && blockElements[name]:
+			p.flush()
+			p.verbatim(i, i+n)
+		case n > 0:
+			p.add(i, i+n, false)
+		case text[i] == '&' && entityPattern.MatchString(text[i:]):
+			n = len(entityPattern.FindString(text[i:]))
+			p.add(i, i+n, false)
+		default:
+			_, n = utf8.DecodeRuneInString(text[i:])
+			p.add(i, i+n, true)
+		}
+		i += n
+	}
+	p.flush()
+	return p.doc
+}
+
+// tagAt returns the length of the comment, declaration or tag that starts at text[i], the
+// lower-case element name of a tag, and whether it is an end tag. The length is 0 if no
+// markup starts there.
+func tagAt(text string, i int) (int, string, bool) {
+	rest := text[i:]
+	if !strings.HasPrefix(rest, "<") {
+		return 0, "", false
+	}
+	for _, pair := range [][2]string{{"<!--", "-->"}, {"<![CDATA[", "]]>"}, {"<!", ">"}, {"<?", ">"}} {
+		if strings.HasPrefix(rest, pair[0]) {
+			end := strings.Index(rest[len(pair[0]):], pair[1])
+			if end < 0 {
+				return len(rest), "", false
+			}
+			return len(pair[0]) + end + len(pair[1]), "", false
+		}
+	}
+
+	m := tagNamePattern.FindStringSubmatch(rest)
+	if m == nil {
+		return 0, "", false
+	}
+	// Find the closing >, skipping over quoted attribute values
+	var quote byte
+	for j := len(m[0]); j < len(rest); j++ {
+		switch c := rest[j]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '>':
+			return j + 1, strings.ToLower(m[1]), rest[1] == '/'
+		case c == '<':
+			return 0, "", false
+		}
+	}
+	return 0, "", false
+}
+
+// Separates reports whether an inline piece of markup is a tag such as <br> or <img>,
+// which splits the text on either side of it into separate words
+func Separates(markup string) bool {
+	n, name, isEnd := tagAt(markup, 0)
+	return n == len(markup) && !isEnd && breakElements[name]
+}
+
+// rawElementEnd returns the offset just past the end tag of a raw element whose content
+// starts at text[start], or the end of the text if it is never closed
+func rawElementEnd(text string, start int, name string) int {
+	end := regexp.MustCompile(`(?i)</` + regexp.QuoteMeta(name) + `\s*>`).FindStringIndex(text[start:])
+	if end == nil {
+		return len(text)
+	}
+	return start + end[1]
+}
+
+// add appends text[start:end] to the text block being collected. Pieces follow each
+// other in the text, so a piece of the same kind extends the slice before it.
+func (p *parser) add(start, end int, prose bool) {
+	p.end = end
+	if n := len(p.inlines); n > 0 && p.inlines[n-1].Prose == prose {
+		p.inlines[n-1].Text = p.doc.Text[start-len(p.inlines[n-1].Text) : end]
+		return
+	}
+	p.inlines = append(p.inlines, Inline{Text: p.doc.Text[start:end], Prose: prose})
+}
+
+// verbatim adds text[start:end], which is copied unchanged, merging it with a verbatim
+// block right before it
+func (p *parser) verbatim(start, end int) {
+	if start == end {
+		return
+	}
+	if n := len(p.doc.Blocks); n > 0 && p.doc.Blocks[n-1].Kind == Verbatim {
+		last := p.doc.Blocks[n-1]
+		last.Raw = p.doc.Text[start-len(last.Raw) : end]
+		return
+	}
+	p.doc.Blocks = append(p.doc.Blocks, &Block{Kind: Verbatim, Raw: p.doc.Text[start:end]})
+}
+
+// flush ends the text block being collected. Whitespace around the text is verbatim, and
+// a block without any text, such as the indentation between two tags, is verbatim too.
+func (p *parser) flush() {
+	inlines := p.inlines
+	p.inlines = nil
+	if len(inlines) == 0 {
+		return
+	}
+	start := p.end
+	for _, inline := range inlines {
+		start -= len(inline.Text)
+	}
+
+	hasText := false
+	for _, inline := range inlines {
+		hasText = hasText || (inline.Prose && strings.TrimSpace(inline.Text) != "")
+	}
+	if !hasText {
+		p.verbatim(start, p.end)
+		return
+	}
+
+	var leading, trailing int
+	if first := &inlines[0]; first.Prose {
+		trimmed := strings.TrimLeft(first.Text, " \t\r\n")
+		leading, first.Text = len(first.Text)-len(trimmed), trimmed
+	}
+	if last := &inlines[len(inlines)-1]; last.Prose {
+		trimmed := strings.TrimRight(last.Text, " \t\r\n")
+		trailing, last.Text = len(last.Text)-len(trimmed), trimmed
+	}
+
+	block := &Block{Kind: Text, Raw: p.doc.Text[start+leading : p.end-trailing]}
+	for _, inline := range inlines {
+		if inline.Text != "" {
+			block.Inlines = append(block.Inlines, inline)
+		}
+	}
+	p.verbatim(start, start+leading)
+	p.doc.Blocks = append(p.doc.Blocks, block)
+	p.verbatim(p.end-trailing, p.end)
+}
+
+// Rewrite rebuilds the document, replacing every text block with what fn returns for it.
+// Verbatim blocks are copied unchanged.
+func (d *Document) Rewrite(fn func(b *Block) string) string {
+	var result strings.Builder
+	for _, b := range d.Blocks {
+		if b.Kind == Verbatim {
+			result.WriteString(b.Raw)
+			continue
+		}
+		result.WriteString(fn(b))
+	}
+	return result.String()
+}
+
+// Mask returns the text with everything but prose blanked out, byte for byte, so checks
+// meant for prose can run on it and report the original positions
+func (d *Document) Mask() string {
+	var result strings.Builder
+	for _, b := range d.Blocks {
+		if b.Kind == Verbatim {
+			result.WriteString(layout.Blank(b.Raw))
+			continue
+		}
+		result.WriteString(layout.MaskInlines(b.Inlines))
+	}
+	return result.String()
+}
@@ -0,0 +1,88 @@
+// Copyright (c) 2025 Spiros Nikoloudakis
+// Licensed under MIT License - see LICENSE file for details
+
+package layout
+
+import "strings"
+
+// Inline is a piece of a block's content: prose to transform, or markup, code or other
+// text to keep as written
+type Inline struct {
+	Text  string
+	Prose bool
+}
+
+// Lines is the verbatim text around the lines of a block's content, such as comment
+// markers or Markdown list markers, so the content can change and be put back in place
+type Lines struct {
+	Prefixes     []string // verbatim start of each line
+	Suffixes     []string // verbatim end of each line
+	Continuation string   // prefix for lines a rewrite adds
+	Newline      string   // line ending between the lines, "\n" or "\r\n"
+	Final        string   // line ending after the last line, often empty
+}
+
+// Assemble puts the line prefixes, suffixes and line endings back around the lines of
+// content. Lines beyond the original ones get the continuation prefix; the last line
+// always gets the last suffix, such as a heading's closing #s or the */ of a comment.
+func (l Lines) Assemble(content string) string {
+	var result strings.Builder
+	lines := strings.Split(content, "\n")
+	last := len(lines) - 1
+	for i, line := range lines {
+		prefix, suffix, ending := l.Continuation, "", l.Newline
+		if i < len(l.Prefixes) {
+			prefix = l.Prefixes[i]
+		}
+		if i < len(l.Suffixes)-1 {
+			suffix = l.Suffixes[i]
+		}
+		if i == last {
+			suffix, ending = l.Suffixes[len(l.Suffixes)-1], l.Final
+		}
+		result.WriteString(prefix + line + suffix + ending)
+	}
+	return result.String()
+}
+
+// Mask assembles the inlines of a block with everything but prose blanked out, the line
+// prefixes and suffixes included
+func (l Lines) Mask(inlines []Inline) string {
+	masked := Lines{
+		Prefixes: make([]string, len(l.Prefixes)),
+		Suffixes: make([]string, len(l.Suffixes)),
+		Newline:  l.Newline,
+		Final:    l.Final,
+	}
+	for i, prefix := range l.Prefixes {
+		masked.Prefixes[i] = Blank(prefix)
+	}
+	for i, suffix := range l.Suffixes {
+		masked.Suffixes[i] = Blank(suffix)
+	}
+	return masked.Assemble(MaskInlines(inlines))
+}
+
+// MaskInlines joins the inlines with everything but prose blanked out, byte for byte
+func MaskInlines(inlines []Inline) string {
+	var result strings.Builder
+	for _, inline := range inlines {
+		if inline.Prose {
+			result.WriteString(inline.Text)
+		} else {
+			result.WriteString(Blank(inline.Text))
+		}
+	}
+	return result.String()
+}
+
+// Blank replaces every byte but line endings with a space
+func Blank(text string) string {
+	masked := []byte(text)
+	for i, c := range masked {
+		if c != '\n' && c != '\r' {
+			masked[i] = ' '
+		}
+	}
+	return string(masked)
+}
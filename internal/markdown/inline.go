@@ -0,0 +1,212 @@
+// Copyright (c) 2025 Spiros Nikoloudakis
+// Licensed under MIT License - see LICENSE file for details
+
+package markdown
+
+import (
+	"go-reloaded/internal/layout"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Inline is a piece of a prose block's content: prose to transform, or Markdown syntax
+// and code to keep as written
+type Inline = layout.Inline
+
+// asciiPunctuation lists the characters a backslash escapes
+const asciiPunctuation = "!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~"
+
+var (
+	autolinkPattern   = regexp.MustCompile(`^<[A-Za-z][A-Za-z0-9+.-]{1,31}:[^\s<>]*>`)
+	emailLinkPattern  = regexp.MustCompile(`^<[A-Za-z0-9.!#$%&'*+/=?^_{|}~-]+@[A-Za-z0-9](?:[A-Za-z0-9.-]*[A-Za-z0-9])?>`)
+	inlineHTMLPattern = regexp.MustCompile(`^(?:<!--[\s\S]*?-->|</?[A-Za-z][A-Za-z0-9-]*(?:\s+[A-Za-z_:][\w.:-]*(?:\s*=\s*(?:"[^"]*"|'[^']*'|[^\s"'=<>` + "`" + `]+))?)*\s*/?>)`)
+	entityPattern     = regexp.MustCompile(`^&(?:#[0-9]{1,7}|#[xX][0-9a-fA-F]{1,6}|[A-Za-z][A-Za-z0-9]{1,31});`)
+)
+
+// Inlines splits the block's content into prose and the inline syntax around it: code
+// spans, autolinks, inline HTML, entities, backslash escapes, emphasis markers, and the
+// brackets and destinations of links and images. Link text and image descriptions stay
+// prose. Joining the Text of every inline gives back the content.
+func (b *Block) Inlines() []Inline {
+	s := b.Content
+	sc := newScanner(s)
+	var inlines []Inline
+	start := 0 // offset of the last inline, which grows while the kind stays the same
+	add := func(i, n int, prose bool) {
+		if k := len(inlines); k > 0 && inlines[k-1].Prose == prose {
+			inlines[k-1].Text = s[start : i+n]
+			return
+		}
+		start = i
+		inlines = append(inlines, Inline{Text: s[i : i+n], Prose: prose})
+	}
+
+	for i := 0; i < len(s); {
+		n, ok := sc.tails[i]
+		if !ok {
+			n = sc.syntaxAt(i)
+		}
+		if n > 0 {
+			add(i, n, false)
+			i += n
+			continue
+		}
+		_, size := utf8.DecodeRuneInString(s[i:])
+		add(i, size, true)
+		i += size
+	}
+	return inlines
+}
+
+// scanner finds the inline syntax of one block's content. Code spans and bracket pairs
+// are looked up in tables built once, so scanning takes about linear time.
+type scanner struct {
+	s        string
+	spans    codeSpans
+	brackets map[int]int // offset of an opening [ or ( → offset of the bracket closing it
+	tails    map[int]int // offset of a link's closing ] → length of "](destination)"
+}
+
+// newScanner prepares a scanner for s
+func newScanner(s string) *scanner {
+	sc := &scanner{s: s, spans: newCodeSpans(s), brackets: map[int]int{}, tails: map[int]int{}}
+	sc.pairBrackets()
+	return sc
+}
+
+// pairBrackets matches [ with ] and ( with ) in one pass, skipping nested pairs, escaped
+// characters and code spans
+func (sc *scanner) pairBrackets() {
+	var squares, rounds []int
+	for j := 0; j < len(sc.s); j++ {
+		switch sc.s[j] {
+		case '\\':
+			j++
+		case '`':
+			if end := sc.spans.end(j); end > 0 {
+				j = end - 1
+			}
+		case '[':
+			squares = append(squares, j)
+		case '(':
+			rounds = append(rounds, j)
+		case ']':
+			if n := len(squares); n > 0 {
+				sc.brackets[squares[n-1]] = j
+				squares = squares[:n-1]
+			}
+		case ')':
+			if n := len(rounds); n > 0 {
+				sc.brackets[rounds[n-1]] = j
+				rounds = rounds[:n-1]
+			}
+		}
+	}
+}
+
+// syntaxAt returns the length of the Markdown syntax that starts at s[i], or 0 for prose.
+// Escaped modifiers such as \(up) are left to the processor, which has its own escapes.
+func (sc *scanner) syntaxAt(i int) int {
+	s := sc.s
+	rest := s[i:]
+	switch s[i] {
+	case '\\':
+		if len(rest) > 1 && rest[1] == '\n' {
+			return 1 // hard line break
+		}
+		if len(rest) > 1 && rest[1] != '(' && strings.IndexByte(asciiPunctuation, rest[1]) >= 0 {
+			return 2
+		}
+	case '`':
+		if end := sc.spans.end(i); end > 0 {
+			return end - i
+		}
+		return len(rest) - len(strings.TrimLeft(rest, "`"))
+	case '<':
+		for _, re := range []*regexp.Regexp{autolinkPattern, emailLinkPattern, inlineHTMLPattern} {
+			if m := re.FindString(rest); m != "" {
+				return len(m)
+			}
+		}
+	case '&':
+		return len(entityPattern.FindString(rest))
+	case '*', '_', '~':
+		// A run of markers next to text opens or closes emphasis; " * " on its own is prose
+		run := len(rest) - len(strings.TrimLeft(rest, rest[:1]))
+		before, _ := utf8.DecodeLastRuneInString(s[:i])
+		after, _ := utf8.DecodeRuneInString(rest[run:])
+		if !isSpace(before) || !isSpace(after) {
+			return run
+		}
+	case '!':
+		if strings.HasPrefix(rest, "![") && sc.linkAt(i+1) {
+			return 2
+		}
+	case '[':
+		if sc.linkAt(i) {
+			return 1
+		}
+	}
+	return 0
+}
+
+// isSpace reports whether a rune is whitespace or the edge of the content
+func isSpace(r rune) bool {
+	return r == utf8.RuneError || unicode.IsSpace(r)
+}
+
+// codeSpans indexes the backtick runs of a text so code spans can be found quickly
+type codeSpans struct {
+	s    string
+	runs map[int][]int // run length → start offsets of the full runs of that length, in order
+}
+
+// newCodeSpans indexes the backtick runs of s
+func newCodeSpans(s string) codeSpans {
+	spans := codeSpans{s: s, runs: map[int][]int{}}
+	for j := 0; j < len(s); {
+		if s[j] != '`' {
+			j++
+			continue
+		}
+		run := len(s[j:]) - len(strings.TrimLeft(s[j:], "`"))
+		spans.runs[run] = append(spans.runs[run], j)
+		j += run
+	}
+	return spans
+}
+
+// end returns the offset just past the code span that opens with the backticks at s[i],
+// or 0 when no later run of exactly as many backticks closes it
+func (c codeSpans) end(i int) int {
+	rest := c.s[i:]
+	run := len(rest) - len(strings.TrimLeft(rest, "`"))
+	starts := c.runs[run]
+	k := sort.SearchInts(starts, i+run)
+	if k == len(starts) {
+		return 0
+	}
+	return starts[k] + run
+}
+
+// linkAt checks whether the [ at s[i] starts a link with an inline destination, [text](url),
+// or a reference, [text][label]. It records the length of the part after the link text.
+func (sc *scanner) linkAt(i int) bool {
+	closing, ok := sc.brackets[i]
+	if !ok || closing+1 >= len(sc.s) {
+		return false
+	}
+	end, ok := -1, false
+	switch sc.s[closing+1] {
+	case '(', '[':
+		end, ok = sc.brackets[closing+1]
+	}
+	if !ok {
+		return false
+	}
+	sc.tails[closing] = end + 1 - closing
+	return true
+}
@@ -0,0 +1,435 @@
+// Copyright (c) 2025 Spiros Nikoloudakis
+// Licensed under MIT License - see LICENSE file for details
+
+package markdown
+
+import (
+	"go-reloaded/internal/layout"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Kind tells what a block of a Markdown document holds
+type Kind int
+
+const (
+	Verbatim  Kind = iota // copied byte-for-byte: code, HTML, front matter, breaks, blank lines, link definitions
+	Paragraph             // prose lines, possibly inside list items and block quotes
+	Heading               // the text of an ATX heading
+	TableCell             // the text of one table cell
+)
+
+// Block is a verbatim part of the document or a unit of prose. The prose lines are
+// stored without their prefixes and suffixes, so Content holds only the text to transform.
+type Block struct {
+	Kind    Kind
+	Raw     string // exact source of the block
+	Content string // prose lines joined by "\n"
+
+	// Prefixes hold indentation, > markers, list markers and heading #s; Suffixes hold
+	// trailing spaces and closing #s; Continuation is e.g. the indentation under a list
+	// marker; Final is empty at the end of the document
+	layout.Lines
+}
+
+// Document holds the blocks of a Markdown text in order; their Raw texts add up to Text
+type Document struct {
+	Text   string
+	Blocks []*Block
+}
+
+var (
+	quotePrefixPattern    = regexp.MustCompile(`^(?:[ \t]{0,3}>[ \t]?)*`)
+	fencePattern          = regexp.MustCompile("^[ \\t]*(`{3,}|~{3,})")
+	thematicBreakPattern  = regexp.MustCompile(`^[ \t]{0,3}(?:(?:\*[ \t]*){3,}|(?:-[ \t]*){3,}|(?:_[ \t]*){3,})$`)
+	setextPattern         = regexp.MustCompile(`^[ \t]{0,3}(?:=+|-+)[ \t]*$`)
+	headingPattern        = regexp.MustCompile(`^[ \t]{0,3}#{1,6}(?:[ \t]+|$)`)
+	closingHashesPattern  = regexp.MustCompile(`(?:[ \t]+#+)?[ \t]*$`)
+	listItemPattern       = regexp.MustCompile(`^[ \t]*(?:[-*+]|\d{1,9}[.)])(?:[ \t]+(?:\[[ xX]\][ \t]+)?|$)`)
+	linkDefinitionPattern = regexp.MustCompile(`^[ \t]{0,3}\[[^\]]+\]:`)
+	delimiterRowPattern   = regexp.MustCompile(`^[ \t]*\|?[ \t]*:?-+:?[ \t]*(?:\|[ \t]*:?-+:?[ \t]*)*\|?[ \t]*$`)
+	htmlCommentPattern    = regexp.MustCompile(`^[ \t]{0,3}<!--`)
+	htmlRawPattern        = regexp.MustCompile(`(?i)^[ \t]{0,3}<(script|pre|style|textarea)(?:[ \t>]|$)`)
+	htmlBlockTagPattern   = regexp.MustCompile(`(?i)^[ \t]{0,3}</?(address|article|aside|blockquote|body|caption|center|details|dialog|dd|div|dl|dt|fieldset|figcaption|figure|footer|form|h[1-6]|head|header|hr|html|iframe|legend|li|link|main|menu|nav|ol|p|section|summary|table|tbody|td|tfoot|th|thead|title|tr|ul)(?:[ \t/>]|$)`)
+	htmlTagLinePattern    = regexp.MustCompile(`^[ \t]{0,3}(?:<[A-Za-z][A-Za-z0-9-]*(?:\s+[A-Za-z_:][\w.:-]*(?:\s*=\s*(?:"[^"]*"|'[^']*'|[^\s"'=<>` + "`" + `]+))?)*\s*/?>|</[A-Za-z][A-Za-z0-9-]*\s*>)[ \t]*$`)
+)
+
+// parser holds the state of one Parse call
+type parser struct {
+	doc        *Document
+	lines      []string        // source lines, each with its line ending
+	i          int             // next line to read
+	listIndent int             // content column of the open list item, or -1
+	pending    strings.Builder // verbatim text not yet added as a block
+}
+
+// Parse splits a Markdown document into verbatim blocks and prose blocks.
+//
+// Front matter, fenced and indented code, HTML blocks, thematic breaks, setext
+// underlines, link reference definitions and table delimiter rows are verbatim.
+// Paragraphs, list items, block quotes, headings and table cells are prose, with their
+// markers kept aside as line prefixes and suffixes.
+func Parse(text string) *Document {
+	p := &parser{doc: &Document{Text: text}, lines: strings.SplitAfter(text, "\n"), listIndent: -1}
+	if last := len(p.lines) - 1; p.lines[last] == "" {
+		p.lines = p.lines[:last]
+	}
+	p.frontMatter()
+	for p.i < len(p.lines) {
+		p.block()
+	}
+	p.endVerbatim()
+	return p.doc
+}
+
+// splitLine separates a line into its block quote markers, its body and its line ending
+func splitLine(line string) (quote, body, ending string) {
+	body = strings.TrimRight(line, "\r\n")
+	ending = line[len(body):]
+	quote = quotePrefixPattern.FindString(body)
+	return quote, body[len(quote):], ending
+}
+
+// indentation returns the column of the first non-blank character, with tabs stopping every 4 columns
+func indentation(body string) int {
+	column := 0
+	for _, r := range body {
+		switch r {
+		case ' ':
+			column++
+		case '\t':
+			column += 4 - column%4
+		default:
+			return column
+		}
+	}
+	return column
+}
+
+// isBlank reports whether a line holds nothing but block quote markers and whitespace
+func isBlank(line string) bool {
+	_, body, _ := splitLine(line)
+	return strings.TrimSpace(body) == ""
+}
+
+// verbatim adds source text that is copied unchanged. Text up to the next prose block
+// is collected and becomes one verbatim block.
+func (p *parser) verbatim(text string) {
+	p.pending.WriteString(text)
+}
+
+// endVerbatim adds the verbatim text collected so far as a block
+func (p *parser) endVerbatim() {
+	if p.pending.Len() == 0 {
+		return
+	}
+	p.doc.Blocks = append(p.doc.Blocks, &Block{Kind: Verbatim, Raw: p.pending.String()})
+	p.pending.Reset()
+}
+
+// take adds the next n lines as a verbatim block
+func (p *parser) take(n int) {
+	end := min(p.i+n, len(p.lines))
+	p.verbatim(strings.Join(p.lines[p.i:end], ""))
+	p.i = end
+}
+
+// takeUntil adds lines up to and including the first one, starting with the current
+// line, for which done returns true, or up to the end of the document
+func (p *parser) takeUntil(done func(line string) bool) {
+	n := 1
+	for p.i+n < len(p.lines) && !done(p.lines[p.i+n-1]) {
+		n++
+	}
+	p.take(n)
+}
+
+// takeWhile adds the current line and the lines after it for which more returns true
+func (p *parser) takeWhile(more func(line string) bool) {
+	n := 1
+	for p.i+n < len(p.lines) && more(p.lines[p.i+n]) {
+		n++
+	}
+	p.take(n)
+}
+
+// frontMatter takes a YAML (---) or TOML (+++) block at the very start of the document
+func (p *parser) frontMatter() {
+	if len(p.lines) == 0 {
+		return
+	}
+	marker := strings.TrimRight(p.lines[0], " \t\r\n")
+	if marker != "---" && marker != "+++" {
+		return
+	}
+	for n := 1; n < len(p.lines); n++ {
+		closing := strings.TrimRight(p.lines[n], " \t\r\n")
+		if closing == marker || (marker == "---" && closing == "...") {
+			p.take(n + 1)
+			return
+		}
+	}
+}
+
+// block reads the block that starts at the current line
+func (p *parser) block() {
+	line := p.lines[p.i]
+	_, body, _ := splitLine(line)
+	indent := indentation(body)
+	isItem := listItemPattern.MatchString(body) && !thematicBreakPattern.MatchString(body)
+	if p.listIndent >= 0 && strings.TrimSpace(body) != "" && indent < p.listIndent && !isItem {
+		p.listIndent = -1
+	}
+	codeIndent := 4
+	if p.listIndent >= 0 {
+		codeIndent = p.listIndent + 4
+	}
+
+	switch {
+	case strings.TrimSpace(body) == "":
+		p.take(1)
+	case indent >= codeIndent:
+		p.takeWhile(func(next string) bool {
+			_, nextBody, _ := splitLine(next)
+			return strings.TrimSpace(nextBody) == "" || indentation(nextBody) >= codeIndent
+		})
+	case fencePattern.MatchString(body):
+		p.fence(body)
+	case htmlCommentPattern.MatchString(body):
+		p.takeUntil(func(next string) bool { return strings.Contains(next, "-->") })
+	case htmlRawPattern.MatchString(body):
+		tag := "</" + strings.ToLower(htmlRawPattern.FindStringSubmatch(body)[1]) + ">"
+		p.takeUntil(func(next string) bool { return strings.Contains(strings.ToLower(next), tag) })
+	case htmlBlockTagPattern.MatchString(body) || htmlTagLinePattern.MatchString(body):
+		p.takeWhile(func(next string) bool { return !isBlank(next) })
+	case thematicBreakPattern.MatchString(body), setextPattern.MatchString(body), linkDefinitionPattern.MatchString(body):
+		p.take(1)
+	case headingPattern.MatchString(body):
+		p.heading()
+	case strings.Contains(body, "|") && p.i+1 < len(p.lines) && p.isDelimiterRow(p.lines[p.i+1]):
+		p.table()
+	default:
+		p.paragraph(isItem)
+	}
+}
+
+// fence takes a fenced code block up to its closing fence, which uses the same character
+// at least as many times, or up to the end of the document
+func (p *parser) fence(body string) {
+	opening := fencePattern.FindStringSubmatch(body)[1]
+	closing := regexp.MustCompile(`^[ \t]*` + regexp.QuoteMeta(opening[:1]) + `{` + strconv.Itoa(len(opening)) + `,}[ \t]*$`)
+	n := 1
+	for p.i+n < len(p.lines) {
+		_, next, _ := splitLine(p.lines[p.i+n])
+		n++
+		if closing.MatchString(next) {
+			break
+		}
+	}
+	p.take(n)
+}
+
+// isDelimiterRow checks for the row of dashes under a table header, such as |---|:--:|
+func (p *parser) isDelimiterRow(line string) bool {
+	_, body, _ := splitLine(line)
+	return strings.Contains(body, "|") && delimiterRowPattern.MatchString(body)
+}
+
+// heading adds an ATX heading, keeping its #s and any closing #s aside
+func (p *parser) heading() {
+	quote, body, ending := splitLine(p.lines[p.i])
+	marker := headingPattern.FindString(body)
+	text := body[len(marker):]
+	closing := closingHashesPattern.FindString(text)
+	if closing == text {
+		p.take(1)
+		return
+	}
+	p.prose(Heading, []string{quote + marker}, []string{text[:len(text)-len(closing)]}, []string{closing}, "", "", ending)
+	p.i++
+}
+
+// table adds a table row by row: the pipes, the spaces around cell text and the delimiter
+// row are verbatim and every cell is a prose block of its own
+func (p *parser) table() {
+	p.row(p.lines[p.i])
+	p.take(1)
+	for p.i < len(p.lines) && !isBlank(p.lines[p.i]) && strings.Contains(p.lines[p.i], "|") {
+		p.row(p.lines[p.i])
+	}
+}
+
+// row adds one table row and moves past it
+func (p *parser) row(line string) {
+	quote, body, ending := splitLine(line)
+	p.verbatim(quote)
+	start := 0
+	for _, cell := range splitCells(body) {
+		p.verbatim(body[start:cell[0]])
+		text := body[cell[0]:cell[1]]
+		trimmed := strings.TrimSpace(text)
+		if trimmed == "" {
+			p.verbatim(text)
+		} else {
+			lead := strings.Index(text, trimmed)
+			p.verbatim(text[:lead])
+			p.prose(TableCell, []string{""}, []string{trimmed}, []string{""}, "", "", "")
+			p.verbatim(text[lead+len(trimmed):])
+		}
+		start = cell[1]
+	}
+	p.verbatim(body[start:] + ending)
+	p.i++
+}
+
+// splitCells returns the byte range of each cell of a table row. Pipes inside code spans
+// and escaped pipes do not separate cells.
+func splitCells(body string) [][2]int {
+	var cells [][2]int
+	spans := newCodeSpans(body)
+	start := 0
+	if trimmed := strings.TrimLeft(body, " \t"); strings.HasPrefix(trimmed, "|") {
+		start = len(body) - len(trimmed) + 1
+	}
+	for i := start; i < len(body); i++ {
+		switch body[i] {
+		case '\\':
+			i++
+		case '`':
+			if end := spans.end(i); end > 0 {
+				i = end - 1
+			}
+		case '|':
+			cells = append(cells, [2]int{start, i})
+			start = i + 1
+		}
+	}
+	if strings.TrimSpace(body[start:]) != "" {
+		cells = append(cells, [2]int{start, len(body)})
+	}
+	return cells
+}
+
+// paragraph adds a paragraph, or the first paragraph of a list item, up to a blank line or
+// a line that starts another block
+func (p *parser) paragraph(isItem bool) {
+	quote, body, ending := splitLine(p.lines[p.i])
+	depth := strings.Count(quote, ">")
+	marker := body[:len(body)-len(strings.TrimLeft(body, " \t"))]
+	continuation := quote + marker
+	if isItem {
+		marker = listItemPattern.FindString(body)
+		p.listIndent = columns(marker)
+		continuation = quote + strings.Repeat(" ", p.listIndent)
+		if strings.TrimSpace(body[len(marker):]) == "" {
+			p.take(1)
+			return
+		}
+	}
+
+	prefixes := []string{quote + marker}
+	lines := []string{body[len(marker):]}
+	newline, final := ending, ending
+	for p.i+len(lines) < len(p.lines) {
+		nextQuote, next, nextEnding := splitLine(p.lines[p.i+len(lines)])
+		nextDepth := strings.Count(nextQuote, ">")
+		if strings.TrimSpace(next) == "" || (nextDepth != depth && nextDepth != 0) || interrupts(next) {
+			break
+		}
+		lead := next[:len(next)-len(strings.TrimLeft(next, " \t"))]
+		prefixes = append(prefixes, nextQuote+lead)
+		lines = append(lines, next[len(lead):])
+		final = nextEnding
+	}
+	if len(prefixes) > 1 {
+		continuation = prefixes[1]
+	}
+
+	suffixes := make([]string, len(lines))
+	for i, line := range lines {
+		text := strings.TrimRight(line, " \t")
+		lines[i], suffixes[i] = text, line[len(text):]
+	}
+	p.prose(Paragraph, prefixes, lines, suffixes, continuation, newline, final)
+	p.i += len(lines)
+}
+
+// interruptPattern matches the list items that may interrupt a paragraph: bullets and
+// items numbered 1, so a wrapped line starting with "1984. " stays in its paragraph
+var interruptPattern = regexp.MustCompile(`^[ \t]*(?:[-*+]|1[.)])[ \t]+\S`)
+
+// interrupts reports whether a line ends the paragraph before it by starting another block
+func interrupts(body string) bool {
+	return interruptPattern.MatchString(body) || headingPattern.MatchString(body) ||
+		fencePattern.MatchString(body) || thematicBreakPattern.MatchString(body) ||
+		setextPattern.MatchString(body) || htmlCommentPattern.MatchString(body) ||
+		htmlBlockTagPattern.MatchString(body) || htmlRawPattern.MatchString(body)
+}
+
+// columns returns the column reached after the text, with tabs stopping every 4 columns
+func columns(text string) int {
+	column := 0
+	for _, r := range text {
+		if r == '\t' {
+			column += 4 - column%4
+		} else {
+			column++
+		}
+	}
+	return column
+}
+
+// prose adds a prose block made of the given lines and the verbatim text around them
+func (p *parser) prose(kind Kind, prefixes, lines, suffixes []string, continuation, newline, final string) {
+	b := &Block{
+		Kind:    kind,
+		Content: strings.Join(lines, "\n"),
+		Lines: layout.Lines{
+			Prefixes:     prefixes,
+			Suffixes:     suffixes,
+			Continuation: continuation,
+			Newline:      newline,
+			Final:        final,
+		},
+	}
+	b.Raw = b.Assemble(b.Content)
+	p.endVerbatim()
+	p.doc.Blocks = append(p.doc.Blocks, b)
+}
+
+// Rewrite rebuilds the document, replacing the content of every prose block with what fn
+// returns for it. Verbatim blocks and the markers around prose are copied unchanged.
+func (d *Document) Rewrite(fn func(b *Block) string) string {
+	var result strings.Builder
+	for _, b := range d.Blocks {
+		if b.Kind == Verbatim {
+			result.WriteString(b.Raw)
+			continue
+		}
+		content := fn(b)
+		switch {
+		case content == b.Content:
+			result.WriteString(b.Raw)
+		case content == "":
+			// The whole block went away, e.g. a line that only held a directive
+		default:
+			result.WriteString(b.Assemble(content))
+		}
+	}
+	return result.String()
+}
+
+// Mask returns the text with everything but prose blanked out, byte for byte, so checks
+// meant for prose can run on it and report the original positions
+func (d *Document) Mask() string {
+	var result strings.Builder
+	for _, b := range d.Blocks {
+		if b.Kind == Verbatim {
+			result.WriteString(layout.Blank(b.Raw))
+			continue
+		}
+		result.WriteString(b.Lines.Mask(b.Inlines()))
+	}
+	return result.String()
+}
@@ -0,0 +1,398 @@
+// Copyright (c) 2025 Spiros Nikoloudakis
+// Licensed under MIT License - see LICENSE file for details
+
+package pairing
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Role tells what a quote or bracket character does in the text
+type Role int
+
+const (
+	Open       Role = iota // opens a region closed by a later mark
+	Close                  // closes the region opened by an earlier mark
+	Apostrophe             // a ' that belongs to a word: don't, 'twas, rock 'n' roll, the students'
+	Unclosed               // opens a region that is never closed
+	Unmatched              // a closing bracket without an opening one
+)
+
+// Mark is a quote or bracket character with its role in the text
+type Mark struct {
+	Char   rune
+	Index  int // rune index, as used in validation error positions
+	Offset int // byte offset
+	Role   Role
+}
+
+// Region is a quoted or bracketed span, from its opening mark to its closing mark
+type Region struct {
+	Open     Mark
+	Close    Mark
+	Parent   *Region
+	Children []*Region
+	depth    int
+	closed   bool
+}
+
+// Tree holds the regions of a text in the order they appear. Regions nest fully,
+// so a quote inside brackets inside a quote is a grandchild of the outer quote.
+type Tree struct {
+	Text     string
+	Regions  []*Region // top-level regions
+	Marks    []Mark    // every quote and bracket character, in order
+	maxDepth int
+}
+
+// closers maps each opening character to the character that closes it. Straight
+// quotes close themselves; ' is also used as an apostrophe.
+var closers = map[rune]rune{
+	'(': ')', '[': ']', '{': '}', '<': '>', '«': '»', '“': '”', '"': '"', '\'': '\'',
+}
+
+// openers maps each closing bracket back to its opening character
+var openers = map[rune]rune{')': '(', ']': '[', '}': '{', '>': '<', '»': '«', '”': '“'}
+
+// elisions are words written with a leading apostrophe, as in 'twas or rock 'n' roll
+var elisions = map[string]bool{
+	"twas": true, "tis": true, "twere": true, "twill": true, "em": true,
+	"cause": true, "til": true, "n": true,
+}
+
+// contractionSuffixes are the endings that follow the apostrophe of a contraction
+var contractionSuffixes = []string{"t", "s", "re", "ve", "ll", "d", "m"}
+
+// parser holds the state of one Parse call
+type parser struct {
+	tree       *Tree
+	runes      []rune
+	stack      []*Region      // open regions, innermost last
+	markIndex  []int          // index in tree.Marks of each open region's mark
+	open       map[rune][]int // stack positions of the open regions started by each character
+	opened     []*Region      // every region in the order it was opened
+	apostrophe map[int]bool
+	balances   map[int]int // lowest quote balance after each ', see scanBalances
+}
+
+// Parse pairs the quotes and brackets of the text into a tree of regions.
+//
+// A ' between letters (don't), in a contraction typed with a space (don 't), in front
+// of an elision ('twas, '90s, 'n') or after a word when no single quote is open (the
+// students') is an apostrophe. Otherwise a ' closes the innermost open ' or opens a
+// new one, and a " closes the innermost open quote if it is a " and opens a new one
+// inside any other quote, so "a 'b "c" d' e" nests three levels deep. A closing mark
+// closes the innermost region it matches; regions opened inside it and never closed
+// are Unclosed. Parsing takes linear time in the length of the text.
+func Parse(text string) *Tree {
+	p := &parser{tree: &Tree{Text: text}, runes: []rune(text), open: map[rune][]int{}, apostrophe: map[int]bool{}}
+	offset := 0
+	for i, r := range p.runes {
+		if _, isOpener := closers[r]; isOpener || openers[r] != 0 {
+			p.add(Mark{Char: r, Index: i, Offset: offset})
+		}
+		offset += utf8.RuneLen(r)
+	}
+	for len(p.stack) > 0 {
+		p.pop(Unclosed)
+	}
+	p.build()
+	return p.tree
+}
+
+// add classifies a mark and updates the open regions
+func (p *parser) add(mark Mark) {
+	switch {
+	case mark.Char == '\'':
+		mark.Role = p.singleQuoteRole(mark.Index)
+	case mark.Char == '"':
+		mark.Role = Open
+		if quote := p.innermostQuote(); quote >= 0 && p.stack[quote].Open.Char == '"' {
+			mark.Role = Close
+		}
+	case openers[mark.Char] != 0:
+		mark.Role = Unmatched
+		if p.innermost(openers[mark.Char]) >= 0 {
+			mark.Role = Close
+		}
+	default:
+		mark.Role = Open
+	}
+
+	switch mark.Role {
+	case Open:
+		region := &Region{Open: mark}
+		p.open[mark.Char] = append(p.open[mark.Char], len(p.stack))
+		p.stack = append(p.stack, region)
+		p.markIndex = append(p.markIndex, len(p.tree.Marks))
+		p.opened = append(p.opened, region)
+		if len(p.stack) > p.tree.maxDepth {
+			p.tree.maxDepth = len(p.stack)
+		}
+	case Close:
+		opener := mark.Char
+		if openers[opener] != 0 {
+			opener = openers[opener]
+		}
+		for p.stack[len(p.stack)-1].Open.Char != opener {
+			p.pop(Unclosed)
+		}
+		p.stack[len(p.stack)-1].Close = mark
+		p.stack[len(p.stack)-1].closed = true
+		p.pop(Close)
+	}
+	p.tree.Marks = append(p.tree.Marks, mark)
+}
+
+// pop removes the innermost open region; an unclosed one has its mark updated
+func (p *parser) pop(role Role) {
+	n := len(p.stack) - 1
+	char := p.stack[n].Open.Char
+	p.open[char] = p.open[char][:len(p.open[char])-1]
+	if role == Unclosed {
+		p.tree.Marks[p.markIndex[n]].Role = Unclosed
+	}
+	p.stack, p.markIndex = p.stack[:n], p.markIndex[:n]
+}
+
+// build links the closed regions into a tree. Closed regions never overlap, so one pass
+// in opening order finds each region's parent; regions inside an unclosed one belong
+// to the enclosing region instead.
+func (p *parser) build() {
+	var enclosing []*Region
+	for _, region := range p.opened {
+		if !region.closed {
+			continue
+		}
+		for len(enclosing) > 0 && enclosing[len(enclosing)-1].Close.Index < region.Open.Index {
+			enclosing = enclosing[:len(enclosing)-1]
+		}
+		if n := len(enclosing); n > 0 {
+			region.Parent = enclosing[n-1]
+			enclosing[n-1].Children = append(enclosing[n-1].Children, region)
+		} else {
+			p.tree.Regions = append(p.tree.Regions, region)
+		}
+		enclosing = append(enclosing, region)
+		region.depth = len(enclosing)
+	}
+}
+
+// innermost returns the stack position of the innermost open region started by char, or -1
+func (p *parser) innermost(char rune) int {
+	if positions := p.open[char]; len(positions) > 0 {
+		return positions[len(positions)-1]
+	}
+	return -1
+}
+
+// innermostQuote returns the stack position of the innermost open quote region,
+// skipping brackets, or -1
+func (p *parser) innermostQuote() int {
+	innermost := -1
+	for _, char := range []rune{'\'', '"', '«', '“'} {
+		if i := p.innermost(char); i > innermost {
+			innermost = i
+		}
+	}
+	return innermost
+}
+
+// at returns the rune at index i, or 0 outside the text
+func (p *parser) at(i int) rune {
+	if i < 0 || i >= len(p.runes) {
+		return 0
+	}
+	return p.runes[i]
+}
+
+// singleQuoteRole decides whether the ' at index i is an apostrophe, opens a quote or closes one
+func (p *parser) singleQuoteRole(i int) Role {
+	prev, next := p.at(i-1), p.at(i+1)
+	switch {
+	case p.apostrophe[i]:
+		return Apostrophe
+	case isWordRune(prev) && isWordRune(next):
+		return Apostrophe
+	case p.isSplitContraction(i):
+		return Apostrophe
+	case !isWordRune(prev) && p.isElision(i):
+		return Apostrophe
+	}
+
+	open := p.innermost('\'') >= 0
+	endsWord := prev != 0 && !unicode.IsSpace(prev) && !strings.ContainsRune(`([{<«“"'`, prev)
+	switch {
+	case endsWord && open && (prev == 's' || prev == 'S') && p.closedLater(i):
+		// 'the students' books' - the quote is closed further on
+		return Apostrophe
+	case endsWord && open:
+		return Close
+	case endsWord && unicode.IsLetter(prev):
+		// the students' books, goin' home
+		return Apostrophe
+	case open && (next == 0 || unicode.IsSpace(next)):
+		return Close
+	}
+	return Open
+}
+
+// isSplitContraction checks for a contraction typed with a stray space, like "don 't".
+// A suffix closed by a quote is a quoted letter instead, as in "the 'd' key".
+func (p *parser) isSplitContraction(i int) bool {
+	if p.at(i-1) != ' ' || !unicode.IsLetter(p.at(i-2)) {
+		return false
+	}
+	for _, suffix := range contractionSuffixes {
+		end := i + 1 + len(suffix)
+		if end <= len(p.runes) && strings.EqualFold(string(p.runes[i+1:end]), suffix) && !unicode.IsLetter(p.at(end)) && p.at(end) != '\'' {
+			return true
+		}
+	}
+	return false
+}
+
+// isElision checks for an apostrophe in front of a shortened word ('twas, 'em, '90s, 'n')
+func (p *parser) isElision(i int) bool {
+	end := i + 1
+	for end < len(p.runes) && isWordRune(p.runes[end]) {
+		end++
+	}
+	word := strings.ToLower(string(p.runes[i+1 : end]))
+
+	switch {
+	case word == "n":
+		// rock 'n' roll: the second ' is an apostrophe too
+		if p.at(end) == '\'' && !isWordRune(p.at(end+1)) {
+			p.apostrophe[end] = true
+			return true
+		}
+		return false
+	case len(word) == 3 && word[2] == 's' && isDigit(word[0]) && isDigit(word[1]):
+		return true // the '90s
+	}
+	return elisions[word]
+}
+
+// closedLater checks whether a ' after the one at index i would close the quote that
+// is open at i, counting the quotes that open and close in between. The answers for
+// every ' are worked out in one pass the first time they are needed.
+func (p *parser) closedLater(i int) bool {
+	if p.balances == nil {
+		p.scanBalances()
+	}
+	return p.balances[i] < 0
+}
+
+// scanBalances records, for every ', the lowest balance of quotes opened minus quotes
+// closed after it and before the next blank line. A balance below zero means a later '
+// closes a quote opened before it.
+func (p *parser) scanBalances() {
+	const none = 1 << 30 // no quotes ahead
+	p.balances = map[int]int{}
+	lowest := none
+	for j := len(p.runes) - 1; j >= 0; j-- {
+		if p.runes[j] == '\n' && p.at(j+1) == '\n' {
+			lowest = none
+			continue
+		}
+		if p.runes[j] != '\'' {
+			continue
+		}
+		p.balances[j] = lowest
+		prev, next := p.at(j-1), p.at(j+1)
+		switch {
+		case isWordRune(prev) && isWordRune(next):
+		case prev != 0 && !unicode.IsSpace(prev):
+			lowest = min(-1, lowest-1)
+		case next != 0 && !unicode.IsSpace(next):
+			lowest = min(1, lowest+1)
+		}
+	}
+}
+
+// isWordRune checks if a rune is a letter or digit
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// isDigit checks if a byte is an ASCII digit
+func isDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
+
+// IsQuote reports whether the region is quoted rather than bracketed
+func (r *Region) IsQuote() bool {
+	switch r.Open.Char {
+	case '\'', '"', '«', '“':
+		return true
+	}
+	return false
+}
+
+// Depth returns how many regions enclose this one; a top-level region has depth 1
+func (r *Region) Depth() int {
+	return r.depth
+}
+
+// Inner returns the text between the region's marks
+func (r *Region) Inner(text string) string {
+	return text[r.Open.Offset+utf8.RuneLen(r.Open.Char) : r.Close.Offset]
+}
+
+// Wrap puts the region's marks around the given content
+func (r *Region) Wrap(content string) string {
+	return string(r.Open.Char) + content + string(r.Close.Char)
+}
+
+// Walk calls fn for every region, outer regions before the regions inside them
+func (t *Tree) Walk(fn func(*Region)) {
+	var walk func([]*Region)
+	walk = func(regions []*Region) {
+		for _, r := range regions {
+			fn(r)
+			walk(r.Children)
+		}
+	}
+	walk(t.Regions)
+}
+
+// MaxDepth returns the deepest nesting reached in the text, counting regions that are
+// never closed, or 0 without regions
+func (t *Tree) MaxDepth() int {
+	return t.maxDepth
+}
+
+// Find returns the marks with the given role, in order
+func (t *Tree) Find(role Role) []Mark {
+	var marks []Mark
+	for _, mark := range t.Marks {
+		if mark.Role == role {
+			marks = append(marks, mark)
+		}
+	}
+	return marks
+}
+
+// Rewrite rebuilds the text, replacing each region with what fn returns for it. fn
+// receives the region's content with the regions inside it already rewritten, so
+// inner regions are always handled first; fn returns r.Wrap(inner) to keep a region.
+func (t *Tree) Rewrite(fn func(r *Region, inner string) string) string {
+	return t.rewrite(t.Regions, 0, len(t.Text), fn)
+}
+
+// rewrite rebuilds text[start:end], which holds the given regions
+func (t *Tree) rewrite(regions []*Region, start, end int, fn func(*Region, string) string) string {
+	var result strings.Builder
+	last := start
+	for _, r := range regions {
+		innerStart := r.Open.Offset + utf8.RuneLen(r.Open.Char)
+		result.WriteString(t.Text[last:r.Open.Offset])
+		result.WriteString(fn(r, t.rewrite(r.Children, innerStart, r.Close.Offset, fn)))
+		last = r.Close.Offset + utf8.RuneLen(r.Close.Char)
+	}
+	result.WriteString(t.Text[last:end])
+	return result.String()
+}
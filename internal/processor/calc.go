@@ -0,0 +1,293 @@
+// Copyright (c) 2025 Spiros Nikoloudakis
+// Licensed under MIT License - see LICENSE file for details
+
+package processor
+
+import (
+	"errors"
+	"fmt"
+	"go-reloaded/internal/validator"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// calcPattern matches the (calc) modifier
+var calcPattern = regexp.MustCompile(`\s*\(calc\)`)
+
+// calcTokenPattern matches whitespace-separated tokens that can belong to an arithmetic
+// expression. A number never ends in a dot, so the year in "in 2020. 3 + 4 (calc)" ends
+// the sentence before the expression instead of joining it.
+var calcTokenPattern = regexp.MustCompile(`^(?:0[xX][0-9A-Fa-f]+|0[bB][01]+|[0-9]+(?:\.[0-9]+)?|\.[0-9]+|[-+*/%^()])+$`)
+
+// MaxCalcResult bounds the magnitude of (calc) results
+const MaxCalcResult = 1e15
+
+// applyCalculations evaluates the arithmetic expression before each (calc) modifier
+func applyCalculations(text string) string {
+	matches := calcPattern.FindAllStringIndex(text, -1)
+
+	// Process matches from right to left to maintain positions
+	for i := len(matches) - 1; i >= 0; i-- {
+		loc := matches[i]
+		before := text[:loc[0]]
+
+		start, ok := findExpressionStart(before)
+		if !ok {
+			continue
+		}
+		expression := before[start:]
+		if len(expression) > validator.MaxExpressionLength {
+			addNumberCorrection(fmt.Sprintf("Warning: expression longer than %d characters - left unchanged", validator.MaxExpressionLength))
+			continue
+		}
+
+		value, err := evaluateExpression(expression)
+		if err != nil {
+			addNumberCorrection(fmt.Sprintf("Warning: %s (calc) %v - left unchanged", expression, err))
+			continue
+		}
+		result := formatCalcResult(value)
+		addNumberCorrection(fmt.Sprintf("Calculated %s → %s", expression, result))
+		text = before[:start] + result + text[loc[1]:]
+	}
+
+	return text
+}
+
+// findExpressionStart walks back over expression tokens and returns where the expression begins
+func findExpressionStart(before string) (int, bool) {
+	tokens := tokenPattern.FindAllStringIndex(before, -1)
+	first := len(tokens)
+	for first > 0 && calcTokenPattern.MatchString(before[tokens[first-1][0]:tokens[first-1][1]]) {
+		first--
+	}
+	if first == len(tokens) {
+		return 0, false
+	}
+
+	start := tokens[first][0]
+	expression := before[start:]
+
+	// Leave opening parentheses that belong to the surrounding text, e.g. "(total 3 + 4 (calc))"
+	opens := strings.Count(expression, "(") - strings.Count(expression, ")")
+	for opens > 0 && start < len(before) && before[start] == '(' {
+		start++
+		opens--
+	}
+	for start < len(before) && before[start] == ' ' {
+		start++
+	}
+	if opens != 0 || !strings.ContainsAny(before[start:], "0123456789") {
+		return 0, false
+	}
+	return start, true
+}
+
+// formatCalcResult prints integers without decimals and trims float noise from fractions.
+// Values are within MaxCalcResult, so integers always fit an int64.
+func formatCalcResult(value float64) string {
+	if value == math.Trunc(value) {
+		return strconv.FormatInt(int64(value), 10)
+	}
+	return strconv.FormatFloat(math.Round(value*1e10)/1e10, 'f', -1, 64)
+}
+
+// calcParser is a small recursive descent parser for (calc) expressions
+type calcParser struct {
+	input string
+	pos   int
+	depth int
+}
+
+// evaluateExpression parses and evaluates an arithmetic expression
+func evaluateExpression(expression string) (float64, error) {
+	p := &calcParser{input: expression}
+	value, err := p.parseSum()
+	if err != nil {
+		return 0, err
+	}
+	p.skipSpaces()
+	if p.pos < len(p.input) {
+		return 0, fmt.Errorf("has unexpected '%c'", p.input[p.pos])
+	}
+	// A bare literal never reaches an operator's check
+	return value, checkCalcResult(value)
+}
+
+func (p *calcParser) skipSpaces() {
+	for p.pos < len(p.input) && p.input[p.pos] == ' ' {
+		p.pos++
+	}
+}
+
+// peek returns the next non-space byte without consuming it
+func (p *calcParser) peek() byte {
+	p.skipSpaces()
+	if p.pos >= len(p.input) {
+		return 0
+	}
+	return p.input[p.pos]
+}
+
+// parseSum handles + and -
+func (p *calcParser) parseSum() (float64, error) {
+	left, err := p.parseProduct()
+	if err != nil {
+		return 0, err
+	}
+	for {
+		op := p.peek()
+		if op != '+' && op != '-' {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.parseProduct()
+		if err != nil {
+			return 0, err
+		}
+		if op == '+' {
+			left += right
+		} else {
+			left -= right
+		}
+		if err := checkCalcResult(left); err != nil {
+			return 0, err
+		}
+	}
+}
+
+// parseProduct handles *, / and %
+func (p *calcParser) parseProduct() (float64, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return 0, err
+	}
+	for {
+		op := p.peek()
+		if op != '*' && op != '/' && op != '%' {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.parseUnary()
+		if err != nil {
+			return 0, err
+		}
+		switch op {
+		case '*':
+			left *= right
+		case '/':
+			if right == 0 {
+				return 0, errors.New("divides by zero")
+			}
+			left /= right
+		case '%':
+			if right == 0 {
+				return 0, errors.New("divides by zero")
+			}
+			left = math.Mod(left, right)
+		}
+		if err := checkCalcResult(left); err != nil {
+			return 0, err
+		}
+	}
+}
+
+// parseUnary handles leading signs, so -2^2 evaluates as -(2^2)
+func (p *calcParser) parseUnary() (float64, error) {
+	switch p.peek() {
+	case '-':
+		p.pos++
+		value, err := p.parseUnary()
+		return -value, err
+	case '+':
+		p.pos++
+		return p.parseUnary()
+	}
+	return p.parsePower()
+}
+
+// parsePower handles right-associative ^
+func (p *calcParser) parsePower() (float64, error) {
+	base, err := p.parsePrimary()
+	if err != nil {
+		return 0, err
+	}
+	if p.peek() != '^' {
+		return base, nil
+	}
+	p.pos++
+	exponent, err := p.parseUnary()
+	if err != nil {
+		return 0, err
+	}
+	value := math.Pow(base, exponent)
+	return value, checkCalcResult(value)
+}
+
+// parsePrimary handles numbers and parenthesized sub-expressions
+func (p *calcParser) parsePrimary() (float64, error) {
+	c := p.peek()
+	if c == '(' {
+		p.depth++
+		if p.depth > validator.MaxNestingDepth {
+			return 0, fmt.Errorf("nests deeper than %d levels", validator.MaxNestingDepth)
+		}
+		p.pos++
+		value, err := p.parseSum()
+		if err != nil {
+			return 0, err
+		}
+		if p.peek() != ')' {
+			return 0, errors.New("has an unclosed parenthesis")
+		}
+		p.pos++
+		p.depth--
+		return value, nil
+	}
+	return p.parseNumber()
+}
+
+// parseNumber reads a decimal, 0x hexadecimal or 0b binary literal
+func (p *calcParser) parseNumber() (float64, error) {
+	p.skipSpaces()
+	start := p.pos
+	for p.pos < len(p.input) && strings.IndexByte("0123456789.xXbBabcdefABCDEF", p.input[p.pos]) >= 0 {
+		p.pos++
+	}
+	literal := p.input[start:p.pos]
+	if literal == "" {
+		return 0, errors.New("is missing a number")
+	}
+
+	lower := strings.ToLower(literal)
+	switch {
+	case strings.HasPrefix(lower, "0x"):
+		val, err := strconv.ParseInt(literal[2:], 16, 64)
+		if err != nil {
+			return 0, fmt.Errorf("has invalid hex literal %s", literal)
+		}
+		return float64(val), nil
+	case strings.HasPrefix(lower, "0b"):
+		val, err := strconv.ParseInt(literal[2:], 2, 64)
+		if err != nil {
+			return 0, fmt.Errorf("has invalid binary literal %s", literal)
+		}
+		return float64(val), nil
+	}
+
+	value, err := strconv.ParseFloat(literal, 64)
+	if err != nil {
+		return 0, fmt.Errorf("has invalid number %s", literal)
+	}
+	return value, nil
+}
+
+// checkCalcResult rejects results that are not finite or exceed MaxCalcResult
+func checkCalcResult(value float64) error {
+	if math.IsNaN(value) || math.IsInf(value, 0) || math.Abs(value) > MaxCalcResult {
+		return fmt.Errorf("exceeds the maximum result of %g", MaxCalcResult)
+	}
+	return nil
+}
@@ -5,187 +5,220 @@ package processor
 
 import (
 	"fmt"
-	"go-reloaded/internal/validator"
+	"go-reloaded/internal/pairing"
 	"regexp"
 	"strconv"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 )
 
-// applyCaseTransformations applies (low), (up), (cap) modifiers with optional count
+// caseModifierNames lists every case modifier accepted in (name) and (name, N) tags
+const caseModifierNames = `low|up|cap|title|sentence|snake|camel|kebab|pascal|const`
+
+// caseModifierPattern matches a case modifier with an optional count, forward count or scope:
+// (up, 2), (up, +3), (cap, sentence), (low, line)
+var caseModifierPattern = regexp.MustCompile(`\((` + caseModifierNames + `)(?:,\s*(\+?\d+|sentence|line))?\)`)
+
+// caseBlockPattern matches (up:start) and (up:end) block markers
+var caseBlockPattern = regexp.MustCompile(`\((` + caseModifierNames + `):(start|end)\)`)
+
+// caseTagPattern matches any modifier tag or block marker, which is never treated as a word
+var caseTagPattern = regexp.MustCompile(`^\([a-z]+(?::[a-z]+)?(?:,[^()]*)?\)$`)
+
+// mergeModifiers join the selected words into a single identifier
+var mergeModifiers = map[string]bool{
+	"snake":  true,
+	"camel":  true,
+	"kebab":  true,
+	"pascal": true,
+	"const":  true,
+}
+
+// titleSmallWords stay lower-case inside (title) unless they start or end the span
+var titleSmallWords = map[string]bool{
+	"a": true, "an": true, "the": true, "and": true, "but": true, "or": true,
+	"nor": true, "for": true, "so": true, "yet": true, "as": true, "at": true,
+	"by": true, "in": true, "of": true, "off": true, "on": true, "per": true,
+	"to": true, "up": true, "via": true, "with": true, "from": true, "into": true,
+	"over": true, "vs": true,
+}
+
+// applyCaseTransformations applies case modifiers such as (up), (title) or (snake, 2)
 func applyCaseTransformations(text string) string {
-	// Handle quoted text first
-	text = processQuotedCaseTransformations(text)
+	// Handle (up:start) ... (up:end) blocks first so modifiers inside them take precedence
+	text = applyCaseBlocks(text)
 	
-	// Handle regular transformations - process from right to left to avoid position shifts
-	re := regexp.MustCompile(`\((low|up|cap)(?:,\s*(\d+))?\)`)
-	allMatches := re.FindAllStringIndex(text, -1)
+	// Handle quoted text next
+	text = processQuotedCaseTransformations(text)
 	
-	// Process matches from right to left to maintain positions
-	for i := len(allMatches) - 1; i >= 0; i-- {
-		loc := allMatches[i]
-		if loc[0] < 0 || loc[1] > len(text) || loc[0] > loc[1] {
-			continue // Skip invalid bounds
-		}
-		modifier, count := parseModifier(text[loc[0]:loc[1]])
-		beforeText := text[:loc[0]]
-		afterText := text[loc[1]:]
-		
-		text = applyCaseModifier(beforeText, afterText, modifier, count)
+	// Handle regular transformations from left to right, so stacked modifiers such as
+	// "word (low) (cap)" apply in the order they are written. The text is split once and
+	// each tag is removed as the walk reaches it.
+	list := splitAroundTags(text)
+	done := wordList{gaps: list.gaps[:1:1]}
+	rest := list
+	for len(rest.words) > 0 {
+		if !isCaseTag(rest.words[0]) {
+			done.words = append(done.words, rest.words[0])
+			done.gaps = append(done.gaps, rest.gaps[1])
+			rest = wordList{words: rest.words[1:], gaps: rest.gaps[1:]}
+			continue
+		}
+		modifier, count, scope := parseModifier(rest.words[0])
+		after := wordList{words: rest.words[1:], gaps: rest.gaps[1:]}
+		if scope == "sentence" || scope == "line" {
+			count = countScopeWords(done, scope)
+		}
+
+		applyCaseModifier(&done, &after, modifier, count, scope == "forward")
+		rest = joinAroundTag(&done, after)
 	}
 	
-	return text
+	return done.String()
+}
+
+// splitAroundTags splits text into words with every case modifier tag as a word of its own
+func splitAroundTags(text string) wordList {
+	list := wordList{gaps: []string{""}}
+	add := func(segment string) {
+		parts := splitWords(segment)
+		list.gaps[len(list.gaps)-1] += parts.gaps[0]
+		list.words = append(list.words, parts.words...)
+		list.gaps = append(list.gaps, parts.gaps[1:]...)
+	}
+	last := 0
+	for _, loc := range caseModifierPattern.FindAllStringIndex(text, -1) {
+		add(text[last:loc[0]])
+		list.words = append(list.words, text[loc[0]:loc[1]])
+		list.gaps = append(list.gaps, "")
+		last = loc[1]
+	}
+	add(text[last:])
+	return list
 }
 
-// processQuotedCaseTransformations handles case transformations within quotes and brackets
+// isCaseTag reports whether a word is a whole case modifier tag
+func isCaseTag(word string) bool {
+	loc := caseModifierPattern.FindStringIndex(word)
+	return loc != nil && loc[0] == 0 && loc[1] == len(word)
+}
+
+// quotedModifierPattern matches the content of a quoted or bracketed span that ends with a
+// case modifier, as in 'hello world (up)'
+var quotedModifierPattern = regexp.MustCompile(`^\s*((?s:.*?))\s*\((` + caseModifierNames + `)\)\s*$`)
+
+// processQuotedCaseTransformations handles case transformations within quotes and brackets.
+// Nested spans are handled from the inside out.
 func processQuotedCaseTransformations(text string) string {
-	result := text
-	
-	// Single quotes
-	re := regexp.MustCompile(`'\s*([^']*?)\s*\((low|up|cap)\)\s*'`)
-	result = re.ReplaceAllStringFunc(result, func(match string) string {
-		parts := re.FindStringSubmatch(match)
-		if len(parts) < 3 {
-			return match
-		}
-		content := strings.TrimSpace(parts[1])
-		modifier := strings.ToLower(parts[2])
-		return "'" + transformText(content, modifier) + "'"
-	})
-	
-	// Double quotes
-	re = regexp.MustCompile(`"\s*([^"]*?)\s*\((low|up|cap)\)\s*"`)
-	result = re.ReplaceAllStringFunc(result, func(match string) string {
-		parts := re.FindStringSubmatch(match)
-		if len(parts) < 3 {
-			return match
-		}
-		content := strings.TrimSpace(parts[1])
-		modifier := strings.ToLower(parts[2])
-		return `"` + transformText(content, modifier) + `"`
-	})
-	
-	// Parentheses
-	re = regexp.MustCompile(`\(\s*([^)]*?)\s*\((low|up|cap)\)\s*\)`)
-	result = re.ReplaceAllStringFunc(result, func(match string) string {
-		parts := re.FindStringSubmatch(match)
-		if len(parts) < 3 {
-			return match
-		}
-		content := strings.TrimSpace(parts[1])
-		modifier := strings.ToLower(parts[2])
-		return "(" + transformText(content, modifier) + ")"
-	})
-	
-	// Square brackets
-	re = regexp.MustCompile(`\[\s*([^\]]*?)\s*\((low|up|cap)\)\s*\]`)
-	result = re.ReplaceAllStringFunc(result, func(match string) string {
-		parts := re.FindStringSubmatch(match)
-		if len(parts) < 3 {
-			return match
-		}
-		content := strings.TrimSpace(parts[1])
-		modifier := strings.ToLower(parts[2])
-		return "[" + transformText(content, modifier) + "]"
-	})
-	
-	// Curly braces
-	re = regexp.MustCompile(`\{\s*([^}]*?)\s*\((low|up|cap)\)\s*\}`)
-	result = re.ReplaceAllStringFunc(result, func(match string) string {
-		parts := re.FindStringSubmatch(match)
-		if len(parts) < 3 {
-			return match
-		}
-		content := strings.TrimSpace(parts[1])
-		modifier := strings.ToLower(parts[2])
-		return "{" + transformText(content, modifier) + "}"
-	})
-	
-	// Angle brackets
-	re = regexp.MustCompile(`<\s*([^>]*?)\s*\((low|up|cap)\)\s*>`)
-	result = re.ReplaceAllStringFunc(result, func(match string) string {
-		parts := re.FindStringSubmatch(match)
-		if len(parts) < 3 {
-			return match
-		}
-		content := strings.TrimSpace(parts[1])
-		modifier := strings.ToLower(parts[2])
-		return "<" + transformText(content, modifier) + ">"
+	return pairing.Parse(text).Rewrite(func(r *pairing.Region, inner string) string {
+		parts := quotedModifierPattern.FindStringSubmatch(inner)
+		if parts == nil {
+			return r.Wrap(inner)
+		}
+		return r.Wrap(transformQuotedText(parts[1], parts[2]))
 	})
-	
-	return result
 }
 
-// parseModifier extracts modifier and count from pattern like (up,2)
-func parseModifier(pattern string) (string, int) {
-	re := regexp.MustCompile(`\((low|up|cap)(?:,\s*(\d+))?\)`)
-	parts := re.FindStringSubmatch(pattern)
+// transformQuotedText applies a modifier to the whole content of a quoted or bracketed span
+func transformQuotedText(content, modifier string) string {
+	switch modifier {
+	case "low", "up", "cap":
+		return transformText(content, modifier)
+	}
+	return strings.Join(transformSpan(strings.Fields(content), modifier), " ")
+}
+
+// parseModifier extracts modifier, count and scope from patterns like (up,2), (up, +3) or (cap, sentence).
+// The scope is "forward" for +N counts, "sentence" or "line" for scoped counts and empty otherwise.
+func parseModifier(pattern string) (string, int, string) {
+	parts := caseModifierPattern.FindStringSubmatch(pattern)
 	if len(parts) < 2 {
-		return "", 1
+		return "", 1, ""
 	}
 	
 	modifier := strings.ToLower(parts[1])
 	count := 1
+	scope := ""
 	if len(parts) > 2 && parts[2] != "" {
-		if c, err := strconv.Atoi(parts[2]); err == nil {
+		arg := parts[2]
+		switch {
+		case arg == "sentence" || arg == "line":
+			scope = arg
+		case strings.HasPrefix(arg, "+"):
+			scope = "forward"
+			arg = arg[1:]
+		}
+		if c, err := strconv.Atoi(arg); err == nil {
 			count = c
 		}
 	}
-	return modifier, count
+	return modifier, count, scope
 }
 
-// applyCaseModifier applies transformation based on count and context
-func applyCaseModifier(beforeText, afterText, modifier string, count int) string {
-	beforeWords := strings.Fields(beforeText)
-	afterWords := strings.Fields(afterText)
-	
-	if count == 1 {
+// countScopeWords counts the words between the start of the current sentence or line and the modifier
+func countScopeWords(before wordList, scope string) int {
+	count := 0
+	for _, word := range before.words[before.scopeStart(scope):] {
+		count += wordWeight(word)
+	}
+	return count
+}
+
+// applyCaseModifier applies transformation based on count and context to the words
+// before and after a tag
+func applyCaseModifier(before, after *wordList, modifier string, count int, forward bool) {
+	if forward {
+		after.setWords(transformForwardWords(after.words, modifier, count, after.countingCeiling()), true)
+	} else if count == 1 {
 		// Single word transformation
-		if len(beforeWords) > 0 {
-			beforeWords[len(beforeWords)-1] = transformText(beforeWords[len(beforeWords)-1], modifier)
+		if n := len(before.words); n > 0 {
+			before.words[n-1] = transformSpan(before.words[n-1:], modifier)[0]
 		}
 	} else {
 		// Multi-word transformation
-		allWords := append(beforeWords, afterWords...)
-		transformMultipleWords(allWords, beforeWords, modifier, count)
-		beforeWords = allWords[:len(beforeWords)]
-		afterWords = allWords[len(beforeWords):]
+		before.setWords(transformMultipleWords(before.words, modifier, count, before.countingFloor()), false)
 	}
-	
-	return reconstructText(beforeWords, afterWords)
 }
 
-// isWord checks if a string is a word (not a number)
+// isWord checks if a string counts as a word under Options.WordCount (by default not a number or a modifier tag)
 func isWord(s string) bool {
-	// Check if it's purely numeric
-	if _, err := strconv.Atoi(s); err == nil {
-		return false
-	}
-	// Check if it contains letters
-	for _, r := range s {
-		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') {
-			return true
-		}
-	}
-	return false
+	return wordWeight(s) > 0
 }
 
 // transformMultipleWords applies transformation to multiple words based on pattern
-func transformMultipleWords(allWords, beforeWords []string, modifier string, count int) {
+// and returns the words before the modifier, which shrink when words are merged.
+// Words before index floor are out of reach under the word counting policy.
+func transformMultipleWords(beforeWords []string, modifier string, count, floor int) []string {
 	// SPECIFICATION COMPLIANT: Multi-word transformations work right-to-left from modifier position
-	if count > 1 {
-		// Transform N words backwards from the modifier position
-		startPos := len(beforeWords) - 1
-		transformed := 0
-		for i := startPos; i >= 0 && transformed < count; i-- {
-			if isWord(allWords[i]) {
-				allWords[i] = transformText(allWords[i], modifier)
-				transformed++
-			}
+	var indexes []int
+	counted := 0
+	for i := len(beforeWords) - 1; i >= floor && counted < count; i-- {
+		if weight := wordWeight(beforeWords[i]); weight > 0 {
+			indexes = append(indexes, i)
+			counted += weight
 		}
+	}
+	if len(indexes) == 0 {
+		return beforeWords
+	}
+	// Indexes were collected right-to-left; put them back in reading order
+	for l, r := 0, len(indexes)-1; l < r; l, r = l+1, r-1 {
+		indexes[l], indexes[r] = indexes[r], indexes[l]
+	}
+	
+	if mergeModifiers[modifier] {
+		// Identifiers take every token between the first selected word and the modifier
+		first := indexes[0]
+		merged := transformSpan(beforeWords[first:], modifier)
+		beforeWords = append(beforeWords[:first], merged...)
 	} else {
-		// Single word transformation: transform the word immediately before modifier
-		if len(beforeWords) > 0 && isWord(allWords[len(beforeWords)-1]) {
-			allWords[len(beforeWords)-1] = transformText(allWords[len(beforeWords)-1], modifier)
+		selected := make([]string, len(indexes))
+		for i, idx := range indexes {
+			selected[i] = beforeWords[idx]
+		}
+		for i, word := range transformSpan(selected, modifier) {
+			beforeWords[indexes[i]] = word
 		}
 	}
 	
@@ -193,6 +226,101 @@ func transformMultipleWords(allWords, beforeWords []string, modifier string, cou
 	if count > 1 {
 		addCaseCorrection(modifier, count)
 	}
+	return beforeWords
+}
+
+// transformForwardWords applies a modifier to the next count words after the modifier,
+// looking no further than the first ceiling words
+func transformForwardWords(afterWords []string, modifier string, count, ceiling int) []string {
+	var indexes []int
+	counted := 0
+	for i := 0; i < len(afterWords) && i < ceiling && counted < count; i++ {
+		if weight := wordWeight(afterWords[i]); weight > 0 {
+			indexes = append(indexes, i)
+			counted += weight
+		}
+	}
+	if len(indexes) == 0 {
+		return afterWords
+	}
+	
+	if mergeModifiers[modifier] {
+		// The merged identifier takes the place of the last merged word, so the words after it stay put
+		last := indexes[len(indexes)-1]
+		merged := transformSpan(afterWords[:last+1], modifier)
+		start := last + 1 - len(merged)
+		copy(afterWords[start:], merged)
+		afterWords = afterWords[start:]
+	} else {
+		selected := make([]string, len(indexes))
+		for i, idx := range indexes {
+			selected[i] = afterWords[idx]
+		}
+		for i, word := range transformSpan(selected, modifier) {
+			afterWords[indexes[i]] = word
+		}
+	}
+	
+	if count > 1 {
+		addCaseCorrection(modifier, count)
+	}
+	return afterWords
+}
+
+// applyCaseBlocks applies (name:start) ... (name:end) blocks to everything between the markers.
+// Outer blocks are applied first so inner blocks and modifiers override them. Unbalanced
+// markers are left in place for the validator to report.
+func applyCaseBlocks(text string) string {
+	markers := caseBlockPattern.FindAllStringSubmatchIndex(text, -1)
+	var stack [][]int
+	for _, m := range markers {
+		name := text[m[2]:m[3]]
+		if text[m[4]:m[5]] == "start" {
+			stack = append(stack, m)
+			continue
+		}
+		if len(stack) == 0 || text[stack[len(stack)-1][2]:stack[len(stack)-1][3]] != name {
+			stack = nil
+			continue
+		}
+		open := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if len(stack) > 0 {
+			continue
+		}
+		
+		content := transformBlock(text[open[1]:m[0]], name)
+		caseCorrections = append(caseCorrections, fmt.Sprintf("Applied %s transformation to a block of %d words", name, len(strings.Fields(content))))
+		return text[:open[0]] + applyCaseBlocks(content) + applyCaseBlocks(text[m[1]:])
+	}
+	return text
+}
+
+// transformBlock applies a modifier to every word of a block, leaving nested tags untouched
+func transformBlock(content, modifier string) string {
+	tokens := tokenPattern.FindAllStringIndex(content, -1)
+	var words []string
+	var positions [][]int
+	for _, loc := range tokens {
+		if !caseTagPattern.MatchString(content[loc[0]:loc[1]]) {
+			words = append(words, content[loc[0]:loc[1]])
+			positions = append(positions, loc)
+		}
+	}
+	if len(words) == 0 {
+		return content
+	}
+	
+	if mergeModifiers[modifier] {
+		first, last := positions[0][0], positions[len(positions)-1][1]
+		return content[:first] + transformSpan(words, modifier)[0] + content[last:]
+	}
+	
+	transformed := transformSpan(words, modifier)
+	for i := len(positions) - 1; i >= 0; i-- {
+		content = content[:positions[i][0]] + transformed[i] + content[positions[i][1]:]
+	}
+	return content
 }
 
 // Global variable to track case corrections
@@ -200,9 +328,9 @@ var caseCorrections []string
 
 func addCaseCorrection(modifier string, count int) {
 	if count > 2 {
-		caseCorrections = append(caseCorrections, fmt.Sprintf("Applied %s transformation to %d words (numbers excluded) - NOTE: Multi-word transformations always start from text beginning", modifier, count))
+		caseCorrections = append(caseCorrections, fmt.Sprintf("Applied %s transformation to %d words (%s) - NOTE: Multi-word transformations always start from text beginning", modifier, count, activeOptions.WordCount))
 	} else {
-		caseCorrections = append(caseCorrections, fmt.Sprintf("Applied %s transformation to %d words (numbers excluded)", modifier, count))
+		caseCorrections = append(caseCorrections, fmt.Sprintf("Applied %s transformation to %d words (%s)", modifier, count, activeOptions.WordCount))
 	}
 }
 
@@ -212,37 +340,239 @@ func getAndClearCaseCorrections() []string {
 	return corrections
 }
 
-// transformText applies case transformation to a single word
+// transformText applies case transformation to a single word.
+// Words in the exception dictionary keep their canonical casing under (low) and (cap).
 func transformText(word, modifier string) string {
 	switch modifier {
 	case "low":
+		if canonical, ok := caseException(word); ok {
+			return canonical
+		}
 		return strings.ToLower(word)
 	case "up":
 		return strings.ToUpper(word)
 	case "cap":
-		if len(word) > 0 {
-			first, ok := validator.SafeIndex(word, 0)
-			if ok {
-				return strings.ToUpper(string(first)) + strings.ToLower(validator.SafeSlice(word, 1, len(word)))
-			}
+		if canonical, ok := caseException(word); ok {
+			return canonical
 		}
+		return capitalizeCompound(word)
 	}
 	return word
 }
 
-// reconstructText rebuilds text from word slices
-func reconstructText(beforeWords, afterWords []string) string {
-	before := strings.Join(beforeWords, " ")
-	after := strings.Join(afterWords, " ")
-	if len(beforeWords) > 0 && len(afterWords) > 0 {
-		return before + " " + after
+// capitalize upper-cases the first letter of a word and lower-cases the rest. Placeholders
+// in front of the word, such as hidden Markdown emphasis markers, are kept as they are.
+func capitalize(word string) string {
+	body := strings.TrimLeftFunc(word, isPlaceholderRune)
+	first, size := utf8.DecodeRuneInString(body)
+	if size == 0 {
+		return word
 	}
-	if len(beforeWords) > 0 {
-		return before
+	return word[:len(word)-len(body)] + strings.ToUpper(string(first)) + strings.ToLower(body[size:])
+}
+
+// transformSpan applies a modifier to a group of consecutive words.
+// Word-level modifiers handle each word alone; (title) and (sentence) look at the
+// word's place in the span, and identifier modifiers merge the span into one word.
+func transformSpan(words []string, modifier string) []string {
+	if len(words) == 0 {
+		return words
+	}
+	if mergeModifiers[modifier] {
+		return []string{joinIdentifier(words, modifier)}
+	}
+	
+	result := make([]string, len(words))
+	for i, word := range words {
+		switch modifier {
+		case "title":
+			core := strings.ToLower(strings.Trim(word, ".,;:!?'\"()[]{}"))
+			if i > 0 && i < len(words)-1 && isTitleSmallWord(core) {
+				result[i] = strings.ToLower(word)
+			} else {
+				result[i] = transformText(word, "cap")
+			}
+		case "sentence":
+			if canonical, ok := caseException(word); ok {
+				result[i] = canonical
+			} else if i == 0 {
+				result[i] = capitalize(word)
+			} else {
+				result[i] = strings.ToLower(word)
+			}
+		default:
+			result[i] = transformText(word, modifier)
+		}
+	}
+	return result
+}
+
+// isTitleSmallWord checks the built-in small words and Options.TitleSmallWords
+func isTitleSmallWord(word string) bool {
+	if titleSmallWords[word] {
+		return true
+	}
+	for _, extra := range activeOptions.TitleSmallWords {
+		if strings.EqualFold(extra, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// joinIdentifier merges words into a snake_case, camelCase, kebab-case, PascalCase or CONST_CASE identifier,
+// keeping punctuation that surrounds the span
+func joinIdentifier(words []string, modifier string) string {
+	joined := strings.Join(words, " ")
+	isPart := func(r rune) bool {
+		return unicode.IsLetter(r) || unicode.IsDigit(r)
 	}
+	prefix := joined[:len(joined)-len(strings.TrimLeftFunc(joined, func(r rune) bool { return !isPart(r) }))]
+	suffix := joined[len(strings.TrimRightFunc(joined, func(r rune) bool { return !isPart(r) })):]
+	parts := strings.FieldsFunc(joined, func(r rune) bool { return !isPart(r) })
+	if len(parts) == 0 {
+		return joined
+	}
+	
+	for i, part := range parts {
+		switch modifier {
+		case "const":
+			parts[i] = strings.ToUpper(part)
+		case "camel":
+			if i == 0 {
+				parts[i] = strings.ToLower(part)
+			} else {
+				parts[i] = capitalize(part)
+			}
+		case "pascal":
+			parts[i] = capitalize(part)
+		default:
+			parts[i] = strings.ToLower(part)
+		}
+	}
+	
+	separator := "_"
+	switch modifier {
+	case "kebab":
+		separator = "-"
+	case "camel", "pascal":
+		separator = ""
+	}
+	return prefix + strings.Join(parts, separator) + suffix
+}
+
+// wordList is a text split into whitespace-separated words, keeping the whitespace
+// between them so line breaks and indentation survive a transformation
+type wordList struct {
+	words []string
+	gaps  []string // gaps[i] comes before words[i]; the last gap ends the text
+}
+
+// splitWords splits text into the same words as strings.Fields, keeping the gaps
+func splitWords(text string) wordList {
+	var list wordList
+	rest := text
+	for {
+		start := strings.IndexFunc(rest, func(r rune) bool { return !unicode.IsSpace(r) })
+		if start < 0 {
+			list.gaps = append(list.gaps, rest)
+			return list
+		}
+		end := strings.IndexFunc(rest[start:], unicode.IsSpace)
+		if end < 0 {
+			end = len(rest)
+		} else {
+			end += start
+		}
+		list.gaps = append(list.gaps, rest[:start])
+		list.words = append(list.words, rest[start:end])
+		rest = rest[end:]
+	}
+}
+
+// setWords replaces the words after a transformation. When words were merged into one
+// identifier, the gaps inside the merged span are dropped: at the start of the list for
+// a forward modifier, at the end for a backward one. An identifier that kept a space,
+// such as "word -" from "word" and "-", stays two words.
+func (l *wordList) setWords(words []string, mergedAtStart bool) {
+	removed := len(l.gaps) - 1 - len(words)
+	if removed <= 0 {
+		l.words = words
+		return
+	}
+
+	last := l.gaps[len(l.gaps)-1]
+	if mergedAtStart {
+		l.gaps[removed] = l.gaps[0]
+		l.gaps = l.gaps[removed:]
+		l.words = words
+		if parts := splitWords(words[0]); len(parts.words) > 1 {
+			l.words = append(parts.words, words[1:]...)
+			l.gaps = append(append([]string{l.gaps[0]}, parts.gaps[1:len(parts.words)]...), l.gaps[1:]...)
+		}
+		return
+	}
+	l.gaps = append(l.gaps[:len(words)], last)
+	l.words = words
+	if parts := splitWords(words[len(words)-1]); len(parts.words) > 1 {
+		l.words = append(words[:len(words)-1], parts.words...)
+		l.gaps = append(l.gaps[:len(l.gaps)-1], parts.gaps[1:len(parts.words)]...)
+		l.gaps = append(l.gaps, last)
+	}
+}
+
+// String rebuilds the text from its words and gaps
+func (l wordList) String() string {
+	var result strings.Builder
+	for i, word := range l.words {
+		result.WriteString(l.gaps[i])
+		result.WriteString(word)
+	}
+	result.WriteString(l.gaps[len(l.gaps)-1])
+	return result.String()
+}
+
+// joinAroundTag joins the words on both sides of a removed modifier tag and returns the
+// words after it. The spaces around the tag become one space, but a line break next to
+// the tag is kept with the indentation that follows it.
+func joinAroundTag(before *wordList, after wordList) wordList {
+	beforeGap, afterGap := before.gaps[len(before.gaps)-1], after.gaps[0]
+
+	gap := ""
+	switch {
+	case strings.Contains(afterGap, "\n"):
+		gap = afterGap[strings.Index(afterGap, "\n"):]
+	case strings.Contains(beforeGap, "\n"):
+		gap = beforeGap[strings.Index(beforeGap, "\n"):]
+	case len(before.words) > 0 && len(after.words) > 0 && !attachesToTag(before, after):
+		gap = " "
+	}
+	before.gaps[len(before.gaps)-1] = gap
+	after.gaps[0] = gap
 	return after
 }
 
+// attachesToTag reports whether markup hidden behind a placeholder was written against the
+// tag and belongs to the word on the far side of it, as the closing ** in "**bold (up)**"
+// or the opening ** in "**(up) bold**", so no space is put between them
+func attachesToTag(before *wordList, after wordList) bool {
+	if after.gaps[0] == "" {
+		word := after.words[0]
+		if rest := strings.TrimLeftFunc(word, isPlaceholderRune); rest != word {
+			r, _ := utf8.DecodeRuneInString(rest)
+			return rest == "" || !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		}
+	}
+	if before.gaps[len(before.gaps)-1] == "" {
+		word := before.words[len(before.words)-1]
+		if rest := strings.TrimRightFunc(word, isPlaceholderRune); rest != word {
+			r, _ := utf8.DecodeLastRuneInString(rest)
+			return rest == "" || !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		}
+	}
+	return false
+}
+
 // min returns the minimum of two integers
 func min(a, b int) int {
 	if a < b {
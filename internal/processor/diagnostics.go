@@ -0,0 +1,335 @@
+// Copyright (c) 2025 Spiros Nikoloudakis
+// Licensed under MIT License - see LICENSE file for details
+
+package processor
+
+import (
+	"fmt"
+	"go-reloaded/internal/validator"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Diagnostic severities
+const (
+	SeverityWarning = "warning"
+	SeverityInfo    = "info"
+)
+
+// Diagnostic describes a likely mistake in the input that does not stop processing
+type Diagnostic struct {
+	Severity string
+	Position int // byte offset in the input
+	Line     int
+	Column   int
+	Message  string
+}
+
+// String formats a diagnostic as "line:column: severity: message"
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%d:%d: %s: %s", d.Line, d.Column, d.Severity, d.Message)
+}
+
+// diagnosticTagPattern matches any single-name tag with an optional argument, e.g. (upp) or (cap, 0)
+var diagnosticTagPattern = regexp.MustCompile(`\(([A-Za-z][A-Za-z0-9_]*)(?:,\s*([^()]*?))?\s*\)`)
+
+// baseLiteralPattern matches the token right before a (hex) or (bin) tag
+var baseLiteralPattern = regexp.MustCompile(`(\S+)\s*$`)
+
+// MaxSuggestionDistance is the largest edit distance for a "did you mean" suggestion.
+// Names shorter than LongModifierName may differ by one edit only, so words like
+// (Table) are not mistaken for (title). Names shorter than ShortModifierName only get a
+// suggestion for swapped or doubled letters, as in (lwo) or (upp), since a single other
+// letter turns words like (see), (bit) or (hey) into modifiers.
+const (
+	MaxSuggestionDistance = 2
+	LongModifierName      = 6
+	ShortModifierName     = 4
+)
+
+// Diagnose inspects the input for modifier mistakes without changing it: unknown or
+// misspelled modifiers, literals with invalid digits for (hex) and (bin), zero counts,
+// and counts larger than the words available. Typos are included when Options.Typos
+// is TyposCheck.
+func Diagnose(text string) []Diagnostic {
+	// Escaped tags and verbatim regions are text, not modifiers
+	checked := validator.MaskVerbatimBytes(maskMarkup(text))
+	for _, re := range []*regexp.Regexp{escapePattern, doubledPattern} {
+		checked = re.ReplaceAllStringFunc(checked, func(match string) string {
+			return strings.Repeat(" ", len(match))
+		})
+	}
+
+	var diagnostics []Diagnostic
+	report := func(pos int, severity, message string) {
+		line := strings.Count(text[:pos], "\n") + 1
+		column := utf8.RuneCountInString(text[strings.LastIndexByte(text[:pos], '\n')+1:pos]) + 1
+		diagnostics = append(diagnostics, Diagnostic{
+			Severity: severity,
+			Position: pos,
+			Line:     line,
+			Column:   column,
+			Message:  message,
+		})
+	}
+
+	counts := &countIndex{text: checked}
+	for _, m := range diagnosticTagPattern.FindAllStringSubmatchIndex(checked, -1) {
+		tag := checked[m[0]:m[1]]
+		names, known := resolveModifierChain(checked[m[2]:m[3]])
+		if !known {
+			if suggestion := suggestModifier(checked[m[2]:m[3]]); suggestion != "" {
+				report(m[0], SeverityWarning, fmt.Sprintf("unknown modifier %s - did you mean (%s)?", tag, suggestion))
+			}
+			continue
+		}
+		arg := ""
+		if m[4] >= 0 {
+			arg = strings.ToLower(strings.TrimSpace(checked[m[4]:m[5]]))
+		}
+
+		for _, name := range names {
+			switch {
+			case name == "hex" || name == "bin":
+				diagnoseBaseLiteral(checked[:m[0]], name, func(message string) {
+					report(m[0], SeverityWarning, message)
+				})
+			case strings.Contains("|"+caseModifierNames+"|", "|"+name+"|"):
+				diagnoseCount(counts, m[0], m[1], name, arg, func(severity, message string) {
+					report(m[0], severity, message)
+				})
+			case name == "wrap":
+				if width, err := strconv.Atoi(arg); err == nil && width < MinWrapWidth {
+					report(m[0], SeverityWarning, fmt.Sprintf("%s is narrower than %d columns - wrapping at %d", tag, MinWrapWidth, MinWrapWidth))
+				}
+			}
+		}
+	}
+	// In check mode the typo rule reports here too, with positions
+	if activeOptions.Typos == TyposCheck {
+		for _, t := range findTypos(checked) {
+			report(t.start, SeverityWarning, fmt.Sprintf("%s '%s' - did you mean '%s'?", strings.ToLower(t.kind), t.original, t.fixed))
+		}
+		sort.SliceStable(diagnostics, func(a, b int) bool { return diagnostics[a].Position < diagnostics[b].Position })
+	}
+	return diagnostics
+}
+
+// diagnoseBaseLiteral reports a (hex) or (bin) tag whose preceding token has invalid digits
+func diagnoseBaseLiteral(before, name string, report func(string)) {
+	parts := baseLiteralPattern.FindStringSubmatch(before)
+	if len(parts) < 2 {
+		report(fmt.Sprintf("(%s) has no number before it", name))
+		return
+	}
+	literal := strings.Trim(parts[1], "'\"([{")
+	base := numberBases[name]
+	if _, err := strconv.ParseUint(trimBasePrefix(literal, base), base, 64); err == nil {
+		return
+	}
+	baseName := "hexadecimal"
+	if name == "bin" {
+		baseName = "binary"
+	}
+	report(fmt.Sprintf("'%s' is not a valid %s number, so (%s) is left unconverted", literal, baseName, name))
+}
+
+// diagnoseCount reports zero counts and counts larger than the words available to the
+// tag between the offsets start and end
+func diagnoseCount(counts *countIndex, start, end int, name, arg string, report func(severity, message string)) {
+	if arg == "" || arg == "sentence" || arg == "line" {
+		return
+	}
+	forward := strings.HasPrefix(arg, "+")
+	count, err := strconv.Atoi(strings.TrimPrefix(arg, "+"))
+	if err != nil {
+		return
+	}
+	if count == 0 {
+		report(SeverityWarning, fmt.Sprintf("(%s, %s) has a count of zero and changes nothing", name, arg))
+		return
+	}
+
+	available := counts.before(start)
+	if forward {
+		available = counts.after(end)
+	}
+	if count > available {
+		verb := "are"
+		if available == 1 {
+			verb = "is"
+		}
+		report(SeverityInfo, fmt.Sprintf("(%s, %s) asks for %d words but only %d %s available", name, arg, count, available, verb))
+	}
+}
+
+// countIndex holds the words, sentence starts and line starts of a text, found once so
+// each count tag is checked without rescanning the text around it
+type countIndex struct {
+	text      string
+	built     bool
+	words     [][]int // byte offsets of each whitespace-separated token
+	weights   []int   // weights[i] is the wordWeight total of words[:i]
+	sentences []int   // SentenceBoundaries of the text, with Options.WordCount.StopAtSentence
+	newlines  []int   // offsets of every "\n"
+}
+
+// build finds the words and boundaries on first use
+func (c *countIndex) build() {
+	if c.built {
+		return
+	}
+	c.built = true
+	c.words = tokenPattern.FindAllStringIndex(c.text, -1)
+	c.weights = make([]int, len(c.words)+1)
+	for i, w := range c.words {
+		c.weights[i+1] = c.weights[i] + wordWeight(c.text[w[0]:w[1]])
+	}
+	if activeOptions.WordCount.StopAtSentence {
+		c.sentences = SentenceBoundaries(c.text)
+	}
+	for i := 0; i < len(c.text); i++ {
+		if c.text[i] == '\n' {
+			c.newlines = append(c.newlines, i)
+		}
+	}
+}
+
+// before returns how many words a backward count at offset pos may reach, as countingFloor
+// would find in the text before pos
+func (c *countIndex) before(pos int) int {
+	c.build()
+	from := 0
+	if c.sentences != nil {
+		// A boundary right at the tag does not count, as the text before it ends there
+		if i := sort.SearchInts(c.sentences, pos); i > 0 {
+			from = c.sentences[i-1]
+		}
+	}
+	if activeOptions.WordCount.StopAtLine {
+		if i := sort.SearchInts(c.newlines, pos); i > 0 {
+			from = max(from, c.newlines[i-1]+1)
+		}
+	}
+	return c.available(from, pos)
+}
+
+// after returns how many words a forward count ending at offset pos may reach, as
+// countingCeiling would find in the text after pos
+func (c *countIndex) after(pos int) int {
+	c.build()
+	to := len(c.text)
+	if c.sentences != nil {
+		if i := sort.SearchInts(c.sentences, pos+1); i < len(c.sentences) {
+			to = c.sentences[i]
+		}
+	}
+	if activeOptions.WordCount.StopAtLine {
+		if i := sort.SearchInts(c.newlines, pos); i < len(c.newlines) {
+			to = min(to, c.newlines[i])
+		}
+	}
+	return c.available(pos, to)
+}
+
+// available returns the weight of the words between the offsets from and to. A word cut
+// by either offset, such as "word(up)", only counts its part inside them.
+func (c *countIndex) available(from, to int) int {
+	first := sort.Search(len(c.words), func(i int) bool { return c.words[i][1] > from })
+	last := sort.Search(len(c.words), func(i int) bool { return c.words[i][0] >= to })
+	if first >= last {
+		return 0
+	}
+	total := c.weights[last] - c.weights[first]
+	for _, i := range []int{first, last - 1} {
+		w := c.words[i]
+		if w[0] < from || w[1] > to {
+			total += wordWeight(c.text[max(w[0], from):min(w[1], to)]) - wordWeight(c.text[w[0]:w[1]])
+		}
+		if first == last-1 {
+			break
+		}
+	}
+	return total
+}
+
+// suggestModifier returns the closest known modifier to a misspelled name, or ""
+func suggestModifier(name string) string {
+	name = strings.ToLower(name)
+	if len(name) < 2 {
+		return ""
+	}
+	best, bestDistance := "", MaxSuggestionDistance+1
+	for known := range knownModifiers {
+		if len(name) < ShortModifierName && !isSwapped(name, known) && !isDoubled(name, known) {
+			continue
+		}
+		if d := editDistance(name, known); d < bestDistance || (d == bestDistance && known < best) {
+			best, bestDistance = known, d
+		}
+	}
+	// Short names need a closer match to avoid flagging ordinary words
+	limit := MaxSuggestionDistance
+	if len(name) < LongModifierName {
+		limit = 1
+	}
+	if bestDistance > limit || bestDistance >= len(name) {
+		return ""
+	}
+	return best
+}
+
+// isSwapped reports whether a is b with two neighbouring letters swapped
+func isSwapped(a, b string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := 0; i+1 < len(a); i++ {
+		if a[i] != b[i] {
+			return a[i] == b[i+1] && a[i+1] == b[i] && a[i+2:] == b[i+2:]
+		}
+	}
+	return false
+}
+
+// isDoubled reports whether a is b with one letter typed twice
+func isDoubled(a, b string) bool {
+	if len(a) != len(b)+1 {
+		return false
+	}
+	for i := 1; i < len(a); i++ {
+		if a[i] == a[i-1] && a[:i]+a[i+1:] == b {
+			return true
+		}
+	}
+	return false
+}
+
+// editDistance computes the edit distance between two strings, counting a swap of
+// two neighbouring letters as one edit so (lwo) still suggests (low)
+func editDistance(a, b string) int {
+	d := make([][]int, len(a)+1)
+	for i := range d {
+		d[i] = make([]int, len(b)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(min(d[i-1][j]+1, d[i][j-1]+1), d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(a)][len(b)]
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2025 Spiros Nikoloudakis
+// Licensed under MIT License - see LICENSE file for details
+
+package processor
+
+import (
+	"fmt"
+	"go-reloaded/internal/validator"
+	"strings"
+)
+
+// Global variable holding the rules disabled for the region being processed
+var disabledRules = map[string]bool{}
+
+// ruleEnabled reports whether a rule is active for the region being processed
+func ruleEnabled(rule string) bool {
+	return !disabledRules[rule]
+}
+
+// Global variable to track regions skipped by directives
+var skippedRegions []string
+
+func addSkippedRegion(region string) {
+	skippedRegions = append(skippedRegions, region)
+}
+
+func getAndClearSkippedRegions() []string {
+	regions := skippedRegions
+	skippedRegions = nil
+	return regions
+}
+
+// processRegions runs the pipeline over each region created by (go-reloaded:...) directives.
+// Verbatim regions are copied unchanged, other regions are processed with their rules
+// disabled, and the directive tags themselves are dropped. Placeholders in the text must
+// come from spans.
+func processRegions(text string, spans *protectedSpans) string {
+	regions := validator.SplitRegions(text)
+	if len(regions) == 1 && regions[0].Start == 0 && regions[0].End == len(text) && !regions[0].Verbatim && len(regions[0].Disabled) == 0 {
+		return runPipeline(text, spans)
+	}
+
+	result := ""
+	for _, region := range regions {
+		segment := text[region.Start:region.End]
+		line := strings.Count(text[:region.Start], "\n") + 1
+
+		if region.Verbatim {
+			addSkippedRegion(fmt.Sprintf("Line %d: %d characters kept verbatim", line, len(strings.TrimSpace(segment))))
+			result = joinRegion(result, segment)
+			continue
+		}
+
+		if len(region.Disabled) > 0 {
+			addSkippedRegion(fmt.Sprintf("Line %d: %s disabled", line, strings.Join(region.Disabled, ", ")))
+		}
+		disabledRules = map[string]bool{}
+		for _, rule := range region.Disabled {
+			disabledRules[rule] = true
+		}
+		regionContext = result
+		processed := strings.TrimSpace(runPipeline(segment, spans))
+		disabledRules = map[string]bool{}
+		regionContext = ""
+
+		// Keep the whitespace that separated this region from its neighbours
+		leading := segment[:len(segment)-len(strings.TrimLeft(segment, " \t\r\n"))]
+		trailing := segment[len(strings.TrimRight(segment, " \t\r\n")):]
+		if processed == "" {
+			result = joinRegion(result, leading)
+			continue
+		}
+		result = joinRegion(result, leading+processed+trailing)
+	}
+	return result
+}
+
+// joinRegion appends a region, collapsing whitespace left on both sides of a removed directive
+func joinRegion(result, region string) string {
+	if result == "" || region == "" {
+		return result + region
+	}
+	resultTrimmed := strings.TrimRight(result, " \t")
+	regionTrimmed := strings.TrimLeft(region, " \t")
+	if len(resultTrimmed) < len(result) && len(regionTrimmed) < len(region) {
+		return result + regionTrimmed
+	}
+	if len(resultTrimmed) < len(result) && strings.HasPrefix(regionTrimmed, "\n") {
+		return resultTrimmed + region
+	}
+	return result + region
+}
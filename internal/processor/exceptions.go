@@ -0,0 +1,95 @@
+// Copyright (c) 2025 Spiros Nikoloudakis
+// Licensed under MIT License - see LICENSE file for details
+
+package processor
+
+import (
+	"strings"
+	"unicode"
+)
+
+// builtinCaseExceptions are words whose canonical casing survives (cap) and (low).
+// Words that are also ordinary English words, such as US or IT, are left out.
+var builtinCaseExceptions = []string{
+	// Acronyms and initialisms
+	"NASA", "FBI", "CIA", "NATO", "UNESCO", "UNICEF", "USA", "UK", "EU", "UN",
+	"BBC", "CNN", "CEO", "CFO", "CTO", "DNA", "RNA", "GPS", "FAQ", "PhD",
+	"API", "CPU", "GPU", "RAM", "SQL", "HTML", "CSS", "JSON", "XML", "YAML",
+	"HTTP", "HTTPS", "URL", "USB", "PDF", "TV",
+	// Brands and names with inner capitals
+	"iPhone", "iPad", "iPod", "iOS", "iCloud", "macOS", "eBay", "YouTube",
+	"GitHub", "GitLab", "JavaScript", "TypeScript", "PowerPoint", "PayPal",
+	"LinkedIn", "WordPress", "McDonald", "MacBook", "DiCaprio",
+}
+
+// apostropheNameExceptions are o'/d' words that are not names and keep a lower-case tail
+var apostropheNameExceptions = map[string]bool{
+	"o'clock": true,
+}
+
+// caseExceptions indexes the built-in exceptions by their lower-case form
+var caseExceptions = buildCaseExceptions()
+
+func buildCaseExceptions() map[string]string {
+	exceptions := make(map[string]string)
+	for _, word := range builtinCaseExceptions {
+		exceptions[strings.ToLower(word)] = word
+	}
+	return exceptions
+}
+
+// caseException returns the canonical casing of a protected word, keeping any
+// surrounding punctuation and a possessive 's, e.g. "nasa's," → "NASA's,"
+func caseException(word string) (string, bool) {
+	isPart := func(r rune) bool {
+		return unicode.IsLetter(r) || unicode.IsDigit(r)
+	}
+	core := strings.TrimFunc(word, func(r rune) bool { return !isPart(r) })
+	if core == "" {
+		return "", false
+	}
+	start := strings.Index(word, core)
+	prefix, suffix := word[:start], word[start+len(core):]
+
+	canonical, ok := lookupCaseException(core)
+	if !ok && len(core) > 2 && strings.HasSuffix(strings.ToLower(core), "'s") {
+		if canonical, ok = lookupCaseException(core[:len(core)-2]); ok {
+			canonical += strings.ToLower(core[len(core)-2:])
+		}
+	}
+	if !ok {
+		return "", false
+	}
+	return prefix + canonical + suffix, true
+}
+
+// lookupCaseException checks Options.CaseExceptions first, then the built-in list
+func lookupCaseException(word string) (string, bool) {
+	for _, extra := range activeOptions.CaseExceptions {
+		if strings.EqualFold(extra, word) {
+			return extra, true
+		}
+	}
+	canonical, ok := caseExceptions[strings.ToLower(word)]
+	return canonical, ok
+}
+
+// capitalizeCompound capitalizes every part of a hyphenated word and the name after
+// an o' or d' prefix: "well-known" → "Well-Known", "o'neil" → "O'Neil"
+func capitalizeCompound(word string) string {
+	parts := strings.Split(word, "-")
+	for i, part := range parts {
+		parts[i] = capitalizeName(part)
+	}
+	return strings.Join(parts, "-")
+}
+
+// capitalizeName capitalizes a word, treating o'neil and d'angelo style names as two parts
+func capitalizeName(word string) string {
+	lower := strings.ToLower(word)
+	bare := strings.TrimRightFunc(lower, func(r rune) bool { return !unicode.IsLetter(r) })
+	if len(bare) >= 4 && bare[1] == '\'' && (bare[0] == 'o' || bare[0] == 'd') && !apostropheNameExceptions[bare] {
+		return strings.ToUpper(word[:1]) + "'" + capitalize(word[2:])
+	}
+	return capitalize(word)
+}
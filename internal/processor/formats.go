@@ -0,0 +1,153 @@
+// Copyright (c) 2025 Spiros Nikoloudakis
+// Licensed under MIT License - see LICENSE file for details
+
+package processor
+
+import (
+	"go-reloaded/internal/htmltext"
+	"go-reloaded/internal/markdown"
+	"go-reloaded/internal/validator"
+	"path/filepath"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Input formats for Options.Format
+const (
+	FormatText     = "text"
+	FormatMarkdown = "markdown"
+	FormatHTML     = "html"
+	FormatGo       = "go"             // comments, and string literals with Options.StringLiterals
+	FormatHash     = "hash-comments"  // # comments of shell, Python, Ruby, YAML and the like
+	FormatSlash    = "slash-comments" // // and /* */ comments of C, Java, JavaScript and the like
+)
+
+// FormatForFile picks the input format from a file name: FormatMarkdown for .md and
+// .markdown files, FormatHTML for .html and .htm files, FormatGo for .go files, the
+// comment formats for the source files of other languages, FormatText otherwise
+func FormatForFile(name string) string {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".md", ".markdown":
+		return FormatMarkdown
+	case ".html", ".htm":
+		return FormatHTML
+	case ".go":
+		return FormatGo
+	case ".py", ".sh", ".bash", ".rb", ".pl", ".r", ".yaml", ".yml", ".toml":
+		return FormatHash
+	case ".c", ".h", ".cc", ".cpp", ".hpp", ".java", ".kt", ".js", ".jsx", ".ts", ".tsx", ".rs", ".swift", ".cs", ".scala":
+		return FormatSlash
+	}
+	return FormatText
+}
+
+// IsSupportedFormat reports whether a format name can be used in Options.Format
+func IsSupportedFormat(format string) bool {
+	switch format {
+	case FormatText, FormatMarkdown, FormatHTML, FormatGo, FormatHash, FormatSlash:
+		return true
+	}
+	return false
+}
+
+// isStructured reports whether the input is a document whose markup must be kept as written
+func isStructured() bool {
+	return activeOptions.Format != "" && activeOptions.Format != FormatText
+}
+
+// processDocument processes plain text, or only the prose of a structured document
+func processDocument(text string) string {
+	switch activeOptions.Format {
+	case FormatMarkdown:
+		return processMarkdown(text)
+	case FormatHTML:
+		return processHTML(text)
+	case FormatGo, FormatHash, FormatSlash:
+		return processSource(parseSource(text))
+	}
+	return processRegions(text, &protectedSpans{})
+}
+
+// proseSpan is a piece of a unit of prose: text to transform, or markup to hide behind a placeholder
+type proseSpan struct {
+	text      string
+	prose     bool
+	separates bool // markup between two words, such as <br>, even with no space around it
+}
+
+// proseProcessor runs the pipeline over the units of prose of one document. A verbatim
+// region or disabled rules left open by a directive carry on into the next unit, so
+// (go-reloaded:off) ... (go-reloaded:on) can span paragraphs.
+type proseProcessor struct {
+	carried string
+}
+
+// process runs the pipeline over one unit of prose and returns it with its markup restored.
+// An unchanged unit comes back exactly as written.
+func (p *proseProcessor) process(pieces []proseSpan) string {
+	spans := &protectedSpans{}
+	var original, content strings.Builder
+	for i, piece := range pieces {
+		original.WriteString(piece.text)
+		switch {
+		case piece.prose:
+			content.WriteString(piece.text)
+		case piece.separates && i > 0 && i < len(pieces)-1 && !endsInSpace(pieces[i-1].text) && !startsWithSpace(pieces[i+1].text):
+			content.WriteString(spans.addSeparator(piece.text))
+		default:
+			content.WriteString(spans.add(piece.text))
+		}
+	}
+
+	source := p.carried + content.String()
+	p.carried = carriedDirective(source)
+	processed := spans.restore(processRegions(source, spans))
+	if processed == strings.TrimSpace(original.String()) {
+		return original.String()
+	}
+	return processed
+}
+
+// startsWithSpace reports whether a text starts with whitespace
+func startsWithSpace(text string) bool {
+	r, size := utf8.DecodeRuneInString(text)
+	return size > 0 && unicode.IsSpace(r)
+}
+
+// endsInSpace reports whether a text ends with whitespace
+func endsInSpace(text string) bool {
+	r, size := utf8.DecodeLastRuneInString(text)
+	return size > 0 && unicode.IsSpace(r)
+}
+
+// carriedDirective returns the directive that restores the state a text ends in: a
+// verbatim region, disabled rules, or nothing
+func carriedDirective(text string) string {
+	regions := validator.SplitRegions(text)
+	if len(regions) == 0 {
+		return ""
+	}
+	last := regions[len(regions)-1]
+	switch {
+	case last.Verbatim:
+		return "(go-reloaded:off)"
+	case len(last.Disabled) > 0:
+		return "(go-reloaded:disable " + strings.Join(last.Disabled, ",") + ")"
+	}
+	return ""
+}
+
+// maskMarkup blanks out everything but prose in structured documents, so validation and
+// diagnostics skip code, markup and link destinations; plain text is returned unchanged
+func maskMarkup(text string) string {
+	switch activeOptions.Format {
+	case FormatMarkdown:
+		return markdown.Parse(text).Mask()
+	case FormatHTML:
+		return htmltext.Parse(text).Mask()
+	case FormatGo, FormatHash, FormatSlash:
+		return parseSource(text).Mask()
+	}
+	return text
+}
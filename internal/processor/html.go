@@ -0,0 +1,22 @@
+// Copyright (c) 2025 Spiros Nikoloudakis
+// Licensed under MIT License - see LICENSE file for details
+
+package processor
+
+import "go-reloaded/internal/htmltext"
+
+// processHTML runs the pipeline over the text nodes of an HTML document only. The text
+// of each block element is processed on its own, with inline tags, entities and <code>
+// elements hidden behind placeholders, so attribute values and entities are never
+// touched; <br>, <img> and <wbr> end the word before them. <script>, <style>, <pre> and <textarea> elements are copied byte-for-byte.
+func processHTML(text string) string {
+	prose := &proseProcessor{}
+	return htmltext.Parse(text).Rewrite(func(b *htmltext.Block) string {
+		var pieces []proseSpan
+		for _, inline := range b.Inlines {
+			separates := !inline.Prose && htmltext.Separates(inline.Text)
+			pieces = append(pieces, proseSpan{text: inline.Text, prose: inline.Prose, separates: separates})
+		}
+		return prose.process(pieces)
+	})
+}
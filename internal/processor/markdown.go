@@ -0,0 +1,21 @@
+// Copyright (c) 2025 Spiros Nikoloudakis
+// Licensed under MIT License - see LICENSE file for details
+
+package processor
+
+import "go-reloaded/internal/markdown"
+
+// processMarkdown runs the pipeline over the prose of a Markdown document only. Each
+// paragraph, heading and table cell is processed on its own, with its inline syntax
+// (code spans, link destinations, emphasis markers, HTML, entities) hidden behind
+// placeholders; code blocks, HTML blocks and front matter are copied byte-for-byte.
+func processMarkdown(text string) string {
+	prose := &proseProcessor{}
+	return markdown.Parse(text).Rewrite(func(b *markdown.Block) string {
+		var pieces []proseSpan
+		for _, inline := range b.Inlines() {
+			pieces = append(pieces, proseSpan{text: inline.Text, prose: inline.Prose})
+		}
+		return prose.process(pieces)
+	})
+}
@@ -0,0 +1,139 @@
+// Copyright (c) 2025 Spiros Nikoloudakis
+// Licensed under MIT License - see LICENSE file for details
+
+package processor
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// numberModifierNames lists every modifier handled by the numeric stage
+const numberModifierNames = `num|calc|hex|bin|roman|toroman|sep|round|ord|pct|words`
+
+// modifierTagPattern matches anything shaped like a modifier tag: a name or chain of names
+// with an optional argument, e.g. (UP), (low|cap, 3) or (Up:Start)
+var modifierTagPattern = regexp.MustCompile(`\(([A-Za-z][A-Za-z0-9_]*(?::[A-Za-z]+)?(?:\s*\|\s*[A-Za-z][A-Za-z0-9_]*)*)\s*(?:,\s*([^()]*?))?\s*\)`)
+
+// knownModifiers holds every modifier name understood by the pipeline
+var knownModifiers = buildKnownModifiers()
+
+func buildKnownModifiers() map[string]bool {
+	known := make(map[string]bool)
+	for _, name := range strings.Split(numberModifierNames+"|"+caseModifierNames+"|"+quoteModifierNames+"|wrap", "|") {
+		known[name] = true
+	}
+	return known
+}
+
+// chainTail is what follows the first link of a modifier chain
+type chainTail struct {
+	head     string // the first link as written out, e.g. "(hex)"
+	rest     string // the tag holding the rest of the chain, e.g. "(sep)"
+	original string // the chain tag as written, e.g. "(hex|sep)"
+}
+
+// chainTails maps the placeholder after the first link of a modifier chain to its tail
+type chainTails map[string]chainTail
+
+// normalizeModifiers rewrites modifier tags into the canonical form the stages expect.
+// Names are matched case-insensitively and aliases from Options.Aliases are resolved.
+// Chains apply in written order, each link to the output of the link before it, so only
+// the first link is written out: (Low|Cap, 3) becomes (low, 3) followed by a placeholder
+// for (cap, 3), which revealChainTails puts back once (low, 3) has been applied.
+// Tags that contain an unknown name are ordinary text and are left untouched.
+func normalizeModifiers(text string, spans *protectedSpans) (string, chainTails) {
+	tails := chainTails{}
+	text = modifierTagPattern.ReplaceAllStringFunc(text, func(match string) string {
+		parts := modifierTagPattern.FindStringSubmatch(match)
+		if len(parts) < 3 {
+			return match
+		}
+
+		names, ok := resolveModifierChain(parts[1])
+		if !ok {
+			return match
+		}
+		arg := strings.ToLower(strings.TrimSpace(parts[2]))
+		if arg != "" {
+			arg = ", " + arg
+		}
+
+		tag := "(" + names[0] + arg + ")"
+		if len(names) > 1 {
+			placeholder := spans.add("")
+			tails[placeholder] = chainTail{head: tag, rest: "(" + strings.Join(names[1:], "|") + arg + ")", original: match}
+			tag += " " + placeholder
+		}
+		return tag
+	})
+	return text, tails
+}
+
+// revealChainTails puts the rest of each modifier chain back in place of its placeholder.
+// A first link still in the text could not be applied, so the whole chain is left as
+// written instead, e.g. "GH (hex|sep)" stays as it is.
+func revealChainTails(text string, tails chainTails, spans *protectedSpans) string {
+	for placeholder, tail := range tails {
+		if failed := tail.head + " " + placeholder; strings.Contains(text, failed) {
+			text = strings.Replace(text, failed, spans.add(tail.original), 1)
+			continue
+		}
+		text = strings.Replace(text, placeholder, tail.rest, 1)
+	}
+	return text
+}
+
+// resolveModifierChain lower-cases and resolves each name in a chain like "Low|u".
+// Block markers such as "up:start" are only valid on their own.
+func resolveModifierChain(chain string) ([]string, bool) {
+	var names []string
+	for _, raw := range strings.Split(chain, "|") {
+		name := strings.ToLower(strings.TrimSpace(raw))
+
+		suffix := ""
+		if colon := strings.Index(name, ":"); colon >= 0 {
+			name, suffix = name[:colon], name[colon:]
+			if (suffix != ":start" && suffix != ":end") || strings.Contains(chain, "|") {
+				return nil, false
+			}
+		}
+
+		for _, resolved := range resolveAlias(name) {
+			if !knownModifiers[resolved] {
+				return nil, false
+			}
+			names = append(names, resolved+suffix)
+		}
+	}
+	return names, true
+}
+
+// resolveAlias expands a user-defined alias, which may itself be a chain such as "low|cap".
+// An alias written exactly as the name wins; otherwise the first alias in sorted order
+// that matches it case-insensitively is used, so the result never depends on map order.
+func resolveAlias(name string) []string {
+	target, ok := activeOptions.Aliases[name]
+	if !ok {
+		aliases := make([]string, 0, len(activeOptions.Aliases))
+		for alias := range activeOptions.Aliases {
+			aliases = append(aliases, alias)
+		}
+		sort.Strings(aliases)
+		for _, alias := range aliases {
+			if strings.EqualFold(alias, name) {
+				target, ok = activeOptions.Aliases[alias], true
+				break
+			}
+		}
+	}
+	if !ok {
+		return []string{name}
+	}
+	var names []string
+	for _, part := range strings.Split(target, "|") {
+		names = append(names, strings.ToLower(strings.TrimSpace(part)))
+	}
+	return names
+}
@@ -4,35 +4,129 @@
 package processor
 
 import (
+	"fmt"
 	"regexp"
 	"strconv"
+	"strings"
 )
 
+// numberPattern matches a literal followed by (hex) or (bin) with an optional width like (hex, s8)
+var numberPattern = regexp.MustCompile(`\b((?:0[xXbB])?[0-9A-Fa-f]+)\s*\((hex|bin)(?:,\s*([su](?:8|16|32|64)))?\)`)
+
+// numberBases maps conversion modifiers to their numeric base
+var numberBases = map[string]int{
+	"hex": 16,
+	"bin": 2,
+}
+
+// Global variable to track number corrections
+var numberCorrections []string
+
+func addNumberCorrection(correction string) {
+	numberCorrections = append(numberCorrections, correction)
+}
+
+func getAndClearNumberCorrections() []string {
+	corrections := numberCorrections
+	numberCorrections = nil
+	return corrections
+}
+
+// applyNumberStage runs every numeric pass in order: style spell-out, then the modifiers
+func applyNumberStage(text string) string {
+	return applyNumberModifiers(applyNumberStyle(text))
+}
+
+// applyNumberModifiers runs the modifier passes in order: (num), (calc), base and Roman
+// conversions, formatting modifiers and finally (words)
+func applyNumberModifiers(text string) string {
+	text = applyWordsToNumber(text)
+	text = applyCalculations(text)
+	text = applyNumberConversions(text)
+	text = applyRomanConversions(text)
+	text = applyNumberFormatting(text)
+	text = applyNumberWords(text)
+	return text
+}
+
 // applyNumberConversions converts (hex) and (bin) notations to decimal
 func applyNumberConversions(text string) string {
-	conversions := map[string]int{
-		"hex": 16,
-		"bin": 2,
-	}
-	
-	for format, base := range conversions {
-		pattern := `\b([0-9A-Fa-f]+)\s*\(` + format + `\)`
-		if format == "bin" {
-			pattern = `\b([01]+)\s*\(bin\)`
+	return numberPattern.ReplaceAllStringFunc(text, func(match string) string {
+		parts := numberPattern.FindStringSubmatch(match)
+		if len(parts) < 4 {
+			return match
 		}
-		
-		re := regexp.MustCompile(pattern)
-		text = re.ReplaceAllStringFunc(text, func(match string) string {
-			parts := re.FindStringSubmatch(match)
-			if len(parts) < 2 {
-				return match
-			}
-			if val, err := strconv.ParseInt(parts[1], base, 64); err == nil {
-				return strconv.FormatInt(val, 10)
+		literal, format, width := parts[1], parts[2], parts[3]
+
+		value, err := convertLiteral(literal, numberBases[format], width)
+		if err != nil {
+			if width != "" {
+				addNumberCorrection(fmt.Sprintf("Warning: %s (%s, %s) %v - left unchanged", literal, format, width, err))
 			}
 			return match
-		})
+		}
+		if width != "" {
+			addNumberCorrection(fmt.Sprintf("Interpreted %s as %s %s → %s", literal, format, width, value))
+		}
+		return value
+	})
+}
+
+// convertLiteral parses a literal in the given base and formats it as decimal.
+// A width such as "s8" or "u32" reads the literal as a fixed-width two's complement value.
+func convertLiteral(literal string, base int, width string) (string, error) {
+	digits := trimBasePrefix(literal, base)
+
+	if width == "" {
+		val, err := strconv.ParseInt(digits, base, 64)
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatInt(val, 10), nil
 	}
-	
-	return text
+
+	signed, bits := parseWidth(width)
+	val, err := strconv.ParseUint(digits, base, 64)
+	if err != nil {
+		if numErr, ok := err.(*strconv.NumError); ok && numErr.Err == strconv.ErrRange {
+			return "", fmt.Errorf("overflows %d bits", bits)
+		}
+		return "", err
+	}
+	if bits < 64 && val>>uint(bits) != 0 {
+		return "", fmt.Errorf("overflows %d bits", bits)
+	}
+
+	if !signed {
+		return strconv.FormatUint(val, 10), nil
+	}
+	if bits == 64 {
+		return strconv.FormatInt(int64(val), 10), nil
+	}
+	signedVal := int64(val)
+	if val&(1<<uint(bits-1)) != 0 {
+		signedVal -= int64(1) << uint(bits)
+	}
+	return strconv.FormatInt(signedVal, 10), nil
+}
+
+// parseWidth splits a width like "s16" into its signedness and bit count
+func parseWidth(width string) (bool, int) {
+	bits, err := strconv.Atoi(width[1:])
+	if err != nil {
+		return false, 64
+	}
+	return width[0] == 's', bits
+}
+
+// trimBasePrefix removes a 0x or 0b prefix matching the requested base
+func trimBasePrefix(literal string, base int) string {
+	lower := strings.ToLower(literal)
+	if base == 16 && strings.HasPrefix(lower, "0x") && len(literal) > 2 {
+		return literal[2:]
+	}
+	if base == 2 && strings.HasPrefix(lower, "0b") && len(literal) > 2 {
+		return literal[2:]
+	}
+	return literal
 }
@@ -0,0 +1,239 @@
+// Copyright (c) 2025 Spiros Nikoloudakis
+// Licensed under MIT License - see LICENSE file for details
+
+package processor
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// numberFormatPattern matches a number followed by (sep), (round), (ord) or (pct) with an
+// optional argument. A minus sign only belongs to the number at the start of a word, which
+// applyNumberFormatting checks, so the 3 in "5-3 (round)" is not negative.
+var numberFormatPattern = regexp.MustCompile(`(-?\b\d+(?:\.\d+)?)\s*\((sep|round|ord|pct)(?:,\s*(\d+))?\)`)
+
+// maxFormatDecimals caps the precision accepted by (round, N) and (pct, N)
+const maxFormatDecimals = 20
+
+// numberLocale describes how numbers are written in a locale
+type numberLocale struct {
+	Group   string
+	Decimal string
+	Percent string
+}
+
+// numberLocales lists the supported locales, keyed by Options.Locale
+var numberLocales = map[string]numberLocale{
+	"en": {Group: ",", Decimal: ".", Percent: "%"},
+	"de": {Group: ".", Decimal: ",", Percent: "\u00a0%"},
+	"fr": {Group: "\u202f", Decimal: ",", Percent: "\u202f%"},
+	"el": {Group: ".", Decimal: ",", Percent: "%"},
+}
+
+// IsSupportedLocale reports whether a locale name can be used in Options.Locale
+func IsSupportedLocale(locale string) bool {
+	_, ok := numberLocales[locale]
+	return ok
+}
+
+// currentNumberLocale returns the number format for the active locale, falling back to English
+func currentNumberLocale() numberLocale {
+	if loc, ok := numberLocales[activeOptions.Locale]; ok {
+		return loc
+	}
+	return numberLocales["en"]
+}
+
+// applyNumberFormatting applies (sep), (round, N), (ord) and (pct) modifiers
+func applyNumberFormatting(text string) string {
+	var result strings.Builder
+	last := 0
+	for _, m := range numberFormatPattern.FindAllStringSubmatchIndex(text, -1) {
+		start, number, modifier := m[0], text[m[2]:m[3]], text[m[4]:m[5]]
+		// A minus right after a word or number is an operator or a hyphen, not a sign
+		if strings.HasPrefix(number, "-") && start > 0 && !isSignBoundary(text[start-1]) {
+			start++
+			number = number[1:]
+		}
+		result.WriteString(text[last:start])
+		last = m[1]
+
+		match := text[start:m[1]]
+		arg := -1
+		if m[6] >= 0 {
+			a, err := strconv.Atoi(text[m[6]:m[7]])
+			if err != nil || a > maxFormatDecimals {
+				// Leave out-of-range precisions untouched
+				result.WriteString(match)
+				continue
+			}
+			arg = a
+		}
+
+		formatted, ok := formatNumber(number, modifier, arg)
+		if !ok {
+			result.WriteString(match)
+			continue
+		}
+		addNumberCorrection(fmt.Sprintf("Formatted %s with %s → %s", number, modifier, formatted))
+		result.WriteString(formatted)
+	}
+	result.WriteString(text[last:])
+	return result.String()
+}
+
+// isSignBoundary reports whether a minus after the byte c starts a negative number
+func isSignBoundary(c byte) bool {
+	return !(c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_' || c == '.' || c == ')' || c >= utf8.RuneSelf)
+}
+
+// formatNumber formats a decimal literal according to a formatting modifier. Rounding
+// and percentages work on the digits as written, so no precision is lost to float64.
+func formatNumber(number, modifier string, arg int) (string, bool) {
+	loc := currentNumberLocale()
+
+	switch modifier {
+	case "sep":
+		return localizeNumber(number, loc, true), true
+	case "round":
+		decimals := arg
+		if decimals < 0 {
+			decimals = 0
+		}
+		return localizeNumber(roundDecimal(number, decimals), loc, false), true
+	case "ord":
+		if strings.Contains(number, ".") {
+			return "", false
+		}
+		return number + ordinalSuffix(number), true
+	case "pct":
+		percent := shiftDecimal(number, 2)
+		if arg >= 0 {
+			percent = roundDecimal(percent, arg)
+		}
+		return localizeNumber(percent, loc, false) + loc.Percent, true
+	}
+	return "", false
+}
+
+// splitDecimal splits a decimal literal such as -12.5 into its sign, integer digits and
+// fraction digits
+func splitDecimal(number string) (sign, intPart, fracPart string) {
+	if strings.HasPrefix(number, "-") {
+		sign, number = "-", number[1:]
+	}
+	intPart = number
+	if dot := strings.Index(number, "."); dot >= 0 {
+		intPart, fracPart = number[:dot], number[dot+1:]
+	}
+	return sign, intPart, fracPart
+}
+
+// joinDecimal writes a decimal literal back without leading zeros, or a sign on zero
+func joinDecimal(sign, intPart, fracPart string) string {
+	intPart = strings.TrimLeft(intPart, "0")
+	if intPart == "" {
+		intPart = "0"
+	}
+	if strings.Trim(intPart+fracPart, "0") == "" {
+		sign = ""
+	}
+	if fracPart != "" {
+		return sign + intPart + "." + fracPart
+	}
+	return sign + intPart
+}
+
+// roundDecimal rounds a decimal literal to the given number of decimals, halves away from zero
+func roundDecimal(number string, decimals int) string {
+	sign, intPart, fracPart := splitDecimal(number)
+	if len(fracPart) <= decimals {
+		return joinDecimal(sign, intPart, fracPart+strings.Repeat("0", decimals-len(fracPart)))
+	}
+
+	digits := []byte(intPart + fracPart[:decimals])
+	if fracPart[decimals] >= '5' {
+		i := len(digits) - 1
+		for ; i >= 0 && digits[i] == '9'; i-- {
+			digits[i] = '0'
+		}
+		if i >= 0 {
+			digits[i]++
+		} else {
+			digits = append([]byte{'1'}, digits...)
+		}
+	}
+	split := len(digits) - decimals
+	return joinDecimal(sign, string(digits[:split]), string(digits[split:]))
+}
+
+// shiftDecimal multiplies a decimal literal by 10^places by moving its point, dropping
+// trailing zeros after it
+func shiftDecimal(number string, places int) string {
+	sign, intPart, fracPart := splitDecimal(number)
+	fracPart += strings.Repeat("0", places)
+	intPart, fracPart = intPart+fracPart[:places], strings.TrimRight(fracPart[places:], "0")
+	return joinDecimal(sign, intPart, fracPart)
+}
+
+// localizeNumber swaps in the locale decimal separator and optionally groups thousands
+func localizeNumber(number string, loc numberLocale, group bool) string {
+	sign, intPart, fracPart := splitDecimal(number)
+
+	if group && len(intPart) > 3 {
+		var grouped strings.Builder
+		lead := len(intPart) % 3
+		if lead > 0 {
+			grouped.WriteString(intPart[:lead])
+		}
+		for i := lead; i < len(intPart); i += 3 {
+			if grouped.Len() > 0 {
+				grouped.WriteString(loc.Group)
+			}
+			grouped.WriteString(intPart[i : i+3])
+		}
+		intPart = grouped.String()
+	}
+
+	if fracPart != "" {
+		return sign + intPart + loc.Decimal + fracPart
+	}
+	return sign + intPart
+}
+
+// ordinalSuffix returns the ordinal ending for an integer in the active locale
+func ordinalSuffix(number string) string {
+	switch activeOptions.Locale {
+	case "de":
+		return "."
+	case "fr":
+		if strings.TrimLeft(number, "-") == "1" {
+			return "er"
+		}
+		return "e"
+	case "el":
+		return "ος"
+	}
+
+	digits := strings.TrimLeft(number, "-")
+	lastTwo := digits
+	if len(digits) > 2 {
+		lastTwo = digits[len(digits)-2:]
+	}
+	if lastTwo == "11" || lastTwo == "12" || lastTwo == "13" {
+		return "th"
+	}
+	switch digits[len(digits)-1] {
+	case '1':
+		return "st"
+	case '2':
+		return "nd"
+	case '3':
+		return "rd"
+	}
+	return "th"
+}
@@ -0,0 +1,345 @@
+// Copyright (c) 2025 Spiros Nikoloudakis
+// Licensed under MIT License - see LICENSE file for details
+
+package processor
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// wordsPattern matches an integer followed by the (words) modifier
+var wordsPattern = regexp.MustCompile(`(-?\b\d+)\s*\(words\)`)
+
+// numPattern matches the (num) modifier that converts preceding number words to digits
+var numPattern = regexp.MustCompile(`\s*\(num\)`)
+
+// bareIntegerPattern matches standalone integers considered by the spell-out style rule
+var bareIntegerPattern = regexp.MustCompile(`\b\d+\b`)
+
+// modifierSpanPattern matches any modifier tag so numbers inside it are left alone
+var modifierSpanPattern = regexp.MustCompile(`\([a-z]+(?:,[^()]*)?\)`)
+
+// tokenPattern matches whitespace-separated tokens with their positions
+var tokenPattern = regexp.MustCompile(`\S+`)
+
+var smallNumberWords = []string{
+	"zero", "one", "two", "three", "four", "five", "six", "seven", "eight", "nine",
+	"ten", "eleven", "twelve", "thirteen", "fourteen", "fifteen", "sixteen",
+	"seventeen", "eighteen", "nineteen",
+}
+
+var tensWords = []string{
+	"", "", "twenty", "thirty", "forty", "fifty", "sixty", "seventy", "eighty", "ninety",
+}
+
+var scaleWords = []struct {
+	Name  string
+	Value int64
+}{
+	{"quintillion", 1000000000000000000},
+	{"quadrillion", 1000000000000000},
+	{"trillion", 1000000000000},
+	{"billion", 1000000000},
+	{"million", 1000000},
+	{"thousand", 1000},
+}
+
+// unitWords are tokens that mark a number as technical, so the style rule keeps its digits
+var unitWords = map[string]bool{
+	"%": true, "percent": true, "mm": true, "cm": true, "m": true, "km": true,
+	"mg": true, "g": true, "kg": true, "t": true, "ml": true, "l": true,
+	"ms": true, "s": true, "sec": true, "min": true, "h": true, "hz": true,
+	"khz": true, "mhz": true, "ghz": true, "b": true, "kb": true, "mb": true,
+	"gb": true, "tb": true, "px": true, "pt": true, "em": true, "v": true,
+	"w": true, "kw": true, "ma": true, "c": true, "f": true,
+	"k": true, "°": true, "°c": true, "°f": true, "ft": true,
+	"mi": true, "lb": true, "lbs": true, "oz": true, "mph": true, "kph": true,
+}
+
+// dateWords are month names and their abbreviations; a day next to one keeps its digits
+var dateWords = map[string]bool{
+	"january": true, "february": true, "march": true, "april": true, "may": true, "june": true,
+	"july": true, "august": true, "september": true, "october": true, "november": true, "december": true,
+	"jan": true, "feb": true, "mar": true, "apr": true, "jun": true, "jul": true,
+	"aug": true, "sep": true, "sept": true, "oct": true, "nov": true, "dec": true,
+}
+
+// versionWords mark the number after them as a version, as in "version 2" or "v 3"
+var versionWords = map[string]bool{"version": true, "ver": true, "v": true, "release": true}
+
+// applyNumberWords spells out integers followed by the (words) modifier
+func applyNumberWords(text string) string {
+	return wordsPattern.ReplaceAllStringFunc(text, func(match string) string {
+		parts := wordsPattern.FindStringSubmatch(match)
+		if len(parts) < 2 {
+			return match
+		}
+		value, err := strconv.ParseInt(parts[1], 10, 64)
+		if err != nil {
+			return match
+		}
+		words := numberToWords(value)
+		addNumberCorrection(fmt.Sprintf("Spelled out %s → %s", parts[1], words))
+		return words
+	})
+}
+
+// applyWordsToNumber replaces number words before each (num) modifier with digits
+func applyWordsToNumber(text string) string {
+	matches := numPattern.FindAllStringIndex(text, -1)
+
+	// Process matches from right to left to maintain positions
+	for i := len(matches) - 1; i >= 0; i-- {
+		loc := matches[i]
+		before := text[:loc[0]]
+		tokens := tokenPattern.FindAllStringIndex(before, -1)
+
+		start := len(tokens)
+		for start > 0 && isNumberWordToken(before[tokens[start-1][0]:tokens[start-1][1]]) {
+			start--
+		}
+		// A leading "and" belongs to the surrounding sentence, not the number
+		for start < len(tokens) && strings.EqualFold(before[tokens[start][0]:tokens[start][1]], "and") {
+			start++
+		}
+		if start == len(tokens) {
+			continue
+		}
+
+		phrase := before[tokens[start][0]:]
+		value, ok := wordsToNumber(phrase)
+		if !ok {
+			continue
+		}
+		digits := strconv.FormatInt(value, 10)
+		addNumberCorrection(fmt.Sprintf("Converted '%s' → %s", strings.TrimSpace(phrase), digits))
+		text = before[:tokens[start][0]] + digits + text[loc[1]:]
+	}
+
+	return text
+}
+
+// applyNumberStyle spells out integers below Options.SpellOutBelow outside technical contexts
+func applyNumberStyle(text string) string {
+	limit := activeOptions.SpellOutBelow
+	if limit <= 0 {
+		return text
+	}
+
+	modifierSpans := modifierSpanPattern.FindAllStringIndex(text, -1)
+	matches := bareIntegerPattern.FindAllStringIndex(text, -1)
+
+	for i := len(matches) - 1; i >= 0; i-- {
+		loc := matches[i]
+		value, err := strconv.Atoi(text[loc[0]:loc[1]])
+		if err != nil || value >= limit || isTechnicalNumber(text, loc, modifierSpans) {
+			continue
+		}
+		words := numberToWords(int64(value))
+		addNumberCorrection(fmt.Sprintf("Spelled out %d → %s (style guide)", value, words))
+		text = text[:loc[0]] + words + text[loc[1]:]
+	}
+
+	return text
+}
+
+// isTechnicalNumber reports whether the integer at loc should keep its digits
+func isTechnicalNumber(text string, loc []int, modifierSpans [][]int) bool {
+	for _, span := range modifierSpans {
+		if loc[0] >= span[0] && loc[1] <= span[1] {
+			return true
+		}
+	}
+
+	// Decimals, versions, times, ranges and currency amounts
+	if loc[0] > 0 && strings.ContainsRune(".,:/-$€£#", rune(text[loc[0]-1])) {
+		return true
+	}
+	if loc[1] < len(text) {
+		next := text[loc[1]]
+		if strings.ContainsRune("%:/", rune(next)) {
+			return true
+		}
+		if (next == '.' || next == ',') && loc[1]+1 < len(text) && text[loc[1]+1] >= '0' && text[loc[1]+1] <= '9' {
+			return true
+		}
+	}
+
+	// Operands of arithmetic such as 3 * (4 + 2)
+	prev := strings.TrimRight(text[:loc[0]], " \t(")
+	rest := strings.TrimLeft(text[loc[1]:], " \t")
+	next := strings.TrimLeft(rest, ") \t")
+	if (prev != "" && strings.ContainsRune("+-*/%^=", rune(prev[len(prev)-1]))) ||
+		(next != "" && strings.ContainsRune("+-*/%^=", rune(next[0]))) {
+		return true
+	}
+
+	// Numbers feeding a modifier such as 10 (bin) or followed by a unit
+	if idx := modifierSpanPattern.FindStringIndex(rest); idx != nil && idx[0] == 0 {
+		return true
+	}
+	if fields := strings.Fields(rest); len(fields) > 0 {
+		unit := strings.ToLower(strings.TrimRight(fields[0], ".,;:!?)\"'"))
+		if unitWords[unit] || dateWords[unit] {
+			return true
+		}
+	}
+
+	// Days after a month and versions, as in "May 3, 2024" or "version 2"
+	if before := strings.TrimRight(text[:loc[0]], " \t"); len(before) < loc[0] {
+		word := strings.ToLower(strings.TrimRight(before[strings.LastIndexAny(before, " \t\n")+1:], "."))
+		if dateWords[word] || versionWords[word] {
+			return true
+		}
+	}
+	return false
+}
+
+// numberToWords spells out an integer in English, e.g. 42 → forty-two
+func numberToWords(n int64) string {
+	if n < 0 {
+		// Negate in uint64, which also holds the magnitude of math.MinInt64
+		return "minus " + magnitudeToWords(-uint64(n))
+	}
+	return magnitudeToWords(uint64(n))
+}
+
+// magnitudeToWords spells out a non-negative integer in English
+func magnitudeToWords(n uint64) string {
+	if n < 20 {
+		return smallNumberWords[n]
+	}
+	if n < 100 {
+		if n%10 == 0 {
+			return tensWords[n/10]
+		}
+		return tensWords[n/10] + "-" + smallNumberWords[n%10]
+	}
+	if n < 1000 {
+		words := smallNumberWords[n/100] + " hundred"
+		if n%100 != 0 {
+			words += " " + magnitudeToWords(n%100)
+		}
+		return words
+	}
+	for _, scale := range scaleWords {
+		if n >= uint64(scale.Value) {
+			words := magnitudeToWords(n/uint64(scale.Value)) + " " + scale.Name
+			if n%uint64(scale.Value) != 0 {
+				words += " " + magnitudeToWords(n%uint64(scale.Value))
+			}
+			return words
+		}
+	}
+	return strconv.FormatUint(n, 10)
+}
+
+// wordsToNumber parses English number words such as "twenty one" or "three hundred and five".
+// Scales must get smaller from left to right, so "one trillion trillion" is rejected, and
+// a unit may only follow a tens word, so "one two three" is rejected too.
+func wordsToNumber(phrase string) (int64, bool) {
+	words := strings.Fields(strings.ToLower(strings.ReplaceAll(phrase, "-", " ")))
+	if len(words) == 0 {
+		return 0, false
+	}
+
+	var total, current int64
+	previous := int64(-1) // value of the previous word when it was a unit or tens word
+	lastScale := int64(math.MaxInt64)
+	negative := false
+	for i, word := range words {
+		if word == "and" {
+			continue
+		}
+		if word == "minus" && i == 0 {
+			negative = true
+			continue
+		}
+		if value, ok := smallNumberValue(word); ok {
+			if previous >= 0 && (previous < 20 || value == 0 || value >= 10) {
+				return 0, false
+			}
+			current += value
+			previous = value
+			continue
+		}
+		previous = -1
+		if word == "hundred" {
+			if current >= 100 {
+				return 0, false
+			}
+			if current == 0 {
+				current = 1
+			}
+			current *= 100
+			continue
+		}
+		scaled := false
+		for _, scale := range scaleWords {
+			if word == scale.Name {
+				if scale.Value >= lastScale || current > (math.MaxInt64-total)/scale.Value {
+					return 0, false
+				}
+				if current == 0 {
+					current = 1
+				}
+				total += current * scale.Value
+				lastScale = scale.Value
+				current = 0
+				scaled = true
+				break
+			}
+		}
+		if !scaled {
+			return 0, false
+		}
+	}
+
+	total += current
+	if negative {
+		total = -total
+	}
+	return total, true
+}
+
+// smallNumberValue returns the value of a unit or tens word
+func smallNumberValue(word string) (int64, bool) {
+	for i, w := range smallNumberWords {
+		if w == word {
+			return int64(i), true
+		}
+	}
+	for i, w := range tensWords {
+		if w != "" && w == word {
+			return int64(i * 10), true
+		}
+	}
+	return 0, false
+}
+
+// isNumberWordToken checks if a token (possibly hyphenated) consists only of number words
+func isNumberWordToken(token string) bool {
+	for _, part := range strings.Split(strings.ToLower(token), "-") {
+		if part == "and" || part == "hundred" || part == "minus" {
+			continue
+		}
+		if _, ok := smallNumberValue(part); ok {
+			continue
+		}
+		isScale := false
+		for _, scale := range scaleWords {
+			if part == scale.Name {
+				isScale = true
+				break
+			}
+		}
+		if !isScale {
+			return false
+		}
+	}
+	return true
+}
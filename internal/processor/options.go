@@ -0,0 +1,104 @@
+// Copyright (c) 2025 Spiros Nikoloudakis
+// Licensed under MIT License - see LICENSE file for details
+
+package processor
+
+// Options holds document-level settings for optional transformations
+type Options struct {
+	// Locale selects number separators: "en" (default), "de", "fr" or "el"
+	Locale string
+
+	// PunctuationProfile selects punctuation spacing, quote marks and ellipses: "en", "fr",
+	// "de" or "el". Empty follows Locale.
+	PunctuationProfile string
+
+	// SpellOutBelow spells out integers below this value in prose (0 disables the rule)
+	SpellOutBelow int
+
+	// StrictRoman rejects non-canonical Roman numerals such as IIII or VX
+	StrictRoman bool
+
+	// TitleSmallWords adds words that (title) keeps lower-case, besides the built-in list
+	TitleSmallWords []string
+
+	// AutoCapitalize capitalizes the first word of every sentence and the pronoun "I"
+	AutoCapitalize bool
+
+	// Typos turns on the repeated-word and typo rule: TyposFix corrects, TyposCheck only reports
+	Typos string
+
+	// RepeatAllowlist adds words that may be repeated on purpose, besides "had had" and "that that"
+	RepeatAllowlist []string
+
+	// TightOperators lists the operator characters that lose the space before them,
+	// e.g. "50 %" → "50%"; AllOperators gives the old behaviour
+	TightOperators string
+
+	// PunctuationRules turns on canonical abbreviations, ellipsis style and spacing,
+	// interrobang ordering and a cap on repeated ! and ?
+	PunctuationRules PunctuationRules
+
+	// Typography turns on smart quotes and dashes (TypographySmart) or converts them back
+	// to ASCII (TypographyASCII)
+	Typography string
+
+	// DashSpacing sets the spaces around dashes made by TypographySmart: DashClosed,
+	// DashSpaced, or empty to keep them as written
+	DashSpacing string
+
+	// QuoteStyle enforces a house style for straight quotes: QuoteStyleDouble puts double
+	// quotes outside and single quotes inside, QuoteStyleSingle the reverse
+	QuoteStyle string
+
+	// Wrap reflows every paragraph to this many columns (0 leaves lines as written)
+	Wrap int
+
+	// Justify pads wrapped lines with spaces so they reach the full width
+	Justify bool
+
+	// Format tells how the input is read: FormatText (the default when empty), or
+	// FormatMarkdown and FormatHTML, which only transform prose and keep code, markup and
+	// links as written, or FormatGo, FormatHash and FormatSlash, which only transform the
+	// comments of source code
+	Format string
+
+	// StringLiterals also transforms the string literals of Go source that read as prose
+	StringLiterals bool
+
+	// CaseExceptions adds words that keep their casing under (cap) and (low), e.g. "gRPC",
+	// besides the built-in acronyms and brand names
+	CaseExceptions []string
+
+	// Aliases maps short modifier names to modifiers or chains, e.g. "u" → "up" or "lc" → "low|cap"
+	Aliases map[string]string
+
+	// WordCount decides which tokens counts such as (up, 3) count as words
+	WordCount WordCountPolicy
+}
+
+// AllOperators lists every operator that punctuation spacing can attach to the word before it
+const AllOperators = `-–—_~*+=|\/%@#$&`
+
+// DefaultTightOperators only attaches the percent sign, so "rock & roll" and "well - known" keep their spaces
+const DefaultTightOperators = `%`
+
+// DefaultOptions returns the settings used when nothing has been configured
+func DefaultOptions() Options {
+	return Options{
+		Locale:         "en",
+		TightOperators: DefaultTightOperators,
+	}
+}
+
+// Global variable holding the settings for the current document
+var activeOptions = DefaultOptions()
+
+// SetOptions replaces the settings used by subsequent processing calls
+func SetOptions(opts Options) {
+	activeOptions = opts
+}
+
+// GetOptions returns the settings currently in effect
+func GetOptions() Options {
+	return activeOptions
+}
@@ -7,27 +7,50 @@ import (
 	"strings"
 )
 
-// ProcessFile is the main entry point for text processing
-func ProcessFile(inputPath, outputPath string) error {
+// ProcessFile is the main entry point for text processing. It also returns the diagnostics
+// for the input, which are skipped when the input fails validation.
+func ProcessFile(inputPath, outputPath string) ([]Diagnostic, error) {
 	content, err := fileio.ReadFile(inputPath)
 	if err != nil {
-		return fmt.Errorf("failed to read input file: %v", err)
+		return nil, fmt.Errorf("failed to read input file: %v", err)
 	}
 
-	processedText := ProcessText(string(content))
+	text := string(content)
+	var diagnostics []Diagnostic
+	processedText := ProcessText(text)
+	if !strings.HasPrefix(processedText, "ERROR: ") {
+		diagnostics = Diagnose(text)
+	}
 
 	err = fileio.WriteFile(outputPath, []byte(processedText))
 	if err != nil {
-		return fmt.Errorf("failed to write output file: %v", err)
+		return nil, fmt.Errorf("failed to write output file: %v", err)
 	}
 
-	return nil
+	return diagnostics, nil
+}
+
+// ValidateText checks the input for security and correctness. In structured formats only
+// the prose is checked, once the input size is known to be within limits. Aliases from
+// Options.Aliases count as the modifiers they stand for.
+func ValidateText(text string) error {
+	if err := validator.ValidateSize(text); err != nil {
+		return err
+	}
+	masked := maskMarkup(text)
+	if err := validator.ValidateInput(masked); err != nil {
+		return err
+	}
+	if len(activeOptions.Aliases) == 0 {
+		return nil
+	}
+	return validator.ValidateTransformations(validator.MaskVerbatim(masked), resolveAlias)
 }
 
 // ProcessText applies all transformations to the input text
 func ProcessText(text string) string {
 	// Validate input for security and correctness
-	if err := validator.ValidateInput(text); err != nil {
+	if err := ValidateText(text); err != nil {
 		return "ERROR: " + err.Error()
 	}
 	
@@ -42,7 +65,7 @@ func ProcessTextUnsafe(text string) string {
 // ProcessTextWithInfo processes text and includes transformation info for web UI
 func ProcessTextWithInfo(text string) string {
 	// Validate input for security and correctness
-	if err := validator.ValidateInput(text); err != nil {
+	if err := ValidateText(text); err != nil {
 		return "ERROR: " + err.Error()
 	}
 	
@@ -54,66 +77,134 @@ func ProcessTextUnsafeWithInfo(text string) string {
 	return processTextInternal(text)
 }
 
-// processTextCore performs core text processing without info messages
-func processTextCore(text string) string {
-	result := text
+// runPipeline applies every transformation stage in order. Placeholders already in the
+// text, such as Markdown syntax, must come from protected, which restores them at the end.
+func runPipeline(text string, protected *protectedSpans) string {
+	// Hide escaped modifiers such as \(up) from every stage
+	result := protectEscapes(text, protected)
 
-	// 1️⃣ Numeric conversions
-	result = applyNumberConversions(result)
+	// 0️⃣ Modifier normalization (case-insensitive names, aliases and chains)
+	result, tails := normalizeModifiers(result, protected)
 
-	// 2️⃣ Case transformations
-	result = applyCaseTransformations(result)
+	// (wrap, N) tags wait for the reflow at the end
+	result, wraps := protectWrapTags(result, protected)
 
-	// 3️⃣ Article corrections
-	result = correctArticles(result)
+	// 1️⃣ Numeric conversions, formatting and number words
+	if ruleEnabled("numbers") {
+		result = applyNumberStage(result)
+	}
 
-	// 4️⃣ Quote formatting
-	result = formatQuotes(result)
+	// 2️⃣ Case transformations (now handles quoted text)
+	if ruleEnabled("case") {
+		result = applyCaseTransformations(result)
+	}
 
-	// 5️⃣ Punctuation formatting
-	result = formatPunctuation(result)
+	// The next link of every modifier chain, applied to the output of the link before it
+	for len(tails) > 0 {
+		result, tails = normalizeModifiers(revealChainTails(result, tails, protected), protected)
+		var more wrapTags
+		result, more = protectWrapTags(result, protected)
+		for placeholder, width := range more {
+			wraps[placeholder] = width
+		}
+		if ruleEnabled("numbers") {
+			result = applyNumberModifiers(result)
+		}
+		if ruleEnabled("case") {
+			result = applyCaseTransformations(result)
+		}
+	}
+	keepLineBreaks = activeOptions.Wrap > 0 || len(wraps) > 0 || isStructured()
+
+	// 2️⃣½ Repeated words and typos (opt-in, before articles so "a a apple" becomes "an apple")
+	if activeOptions.Typos != "" && ruleEnabled("typos") {
+		result = correctTypos(result)
+	}
+
+	// 3️⃣ Article corrections (a → an)
+	if ruleEnabled("articles") {
+		result = correctArticles(result)
+	}
+
+	// 3️⃣½ Sentence capitalization (opt-in)
+	if activeOptions.AutoCapitalize && ruleEnabled("capitalization") {
+		result = capitalizeSentences(result)
+	}
+
+	// Keep URLs, emails, paths, versions, numbers, math and inline code out of the formatting rules
+	result = protectTokens(result, protected)
+
+	// 4️⃣ Quote formatting (spacing)
+	if ruleEnabled("quotes") {
+		result = formatQuotes(result)
+	}
+
+	// 5️⃣ Punctuation formatting (final cleanup)
+	if ruleEnabled("punctuation") {
+		result = formatPunctuation(result)
+	}
+
+	// 6️⃣ Typography (opt-in curly quotes and dashes, or plain ASCII)
+	if activeOptions.Typography != "" && ruleEnabled("typography") {
+		result = applyTypography(result)
+	}
+
+	// 7️⃣ Paragraph reflow (Options.Wrap or (wrap, N) tags), measuring protected tokens as written
+	result = wrapParagraphs(result, wraps, func(word string) int {
+		return displayWidth(protected.restore(word))
+	})
+
+	return protected.restore(result)
+}
+
+// processTextCore performs core text processing without info messages
+func processTextCore(text string) string {
+	result := processDocument(text)
 
 	// Clear tracking data without using it
+	getAndClearNumberCorrections()
 	getAndClearArticleCorrections()
+	getAndClearTypoCorrections()
 	getAndClearCaseCorrections()
 	getAndClearPunctuationCorrections()
 	getAndClearQuoteCorrections()
+	getAndClearSkippedRegions()
 
+	// Structured documents keep their leading and trailing whitespace, like their code blocks
+	if isStructured() {
+		return result
+	}
 	return strings.TrimSpace(result)
 }
 
 // processTextInternal performs text processing with info messages
 func processTextInternal(text string) string {
-	result := text
-
-	// 1️⃣ Numeric conversions
-	result = applyNumberConversions(result)
-
-	// 2️⃣ Case transformations (now handles quoted text)
-	result = applyCaseTransformations(result)
-
-	// 3️⃣ Article corrections (a → an)
-	result = correctArticles(result)
-
-	// 4️⃣ Quote formatting (spacing)
-	result = formatQuotes(result)
-
-	// 5️⃣ Punctuation formatting (final cleanup)
-	result = formatPunctuation(result)
+	result := processDocument(text)
 
 	// Check for corrections and append info
+	numberCorrections := getAndClearNumberCorrections()
 	articleCorrections := getAndClearArticleCorrections()
+	typoCorrections := getAndClearTypoCorrections()
 	caseCorrections := getAndClearCaseCorrections()
 	punctuationCorrections := getAndClearPunctuationCorrections()
 	quoteCorrections := getAndClearQuoteCorrections()
+	skipped := getAndClearSkippedRegions()
 	
-	if len(articleCorrections) > 0 || len(caseCorrections) > 0 || len(punctuationCorrections) > 0 || len(quoteCorrections) > 0 {
+	if len(numberCorrections) > 0 || len(articleCorrections) > 0 || len(typoCorrections) > 0 || len(caseCorrections) > 0 || len(punctuationCorrections) > 0 || len(quoteCorrections) > 0 || len(skipped) > 0 {
 		result += "\n\nINFO: Transformations applied:\n"
 		
+		for _, correction := range numberCorrections {
+			result += fmt.Sprintf("• Number: %s\n", correction)
+		}
+		
 		for _, correction := range articleCorrections {
 			result += fmt.Sprintf("• Article: '%s' → '%s'\n", correction.Original, correction.Corrected)
 		}
 		
+		for _, correction := range typoCorrections {
+			result += fmt.Sprintf("• Typo: %s\n", correction)
+		}
+		
 		for _, correction := range caseCorrections {
 			result += fmt.Sprintf("• Case: %s\n", correction)
 		}
@@ -125,6 +216,10 @@ func processTextInternal(text string) string {
 		for _, correction := range quoteCorrections {
 			result += fmt.Sprintf("• Quotes: %s\n", correction)
 		}
+		
+		for _, region := range skipped {
+			result += fmt.Sprintf("• Skipped: %s\n", region)
+		}
 	}
 
 	return strings.TrimSpace(result)
@@ -0,0 +1,157 @@
+// Copyright (c) 2025 Spiros Nikoloudakis
+// Licensed under MIT License - see LICENSE file for details
+
+package processor
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Protected spans are swapped for placeholders made only of private-use runes, which the
+// validator never lets through, so no rule can match or alter them. The index is written
+// with private-use digits too, so placeholders contain no letters or numbers and word
+// counting, number rules and case rules all skip them.
+const (
+	placeholderOpen  = '\uE000'
+	placeholderClose = '\uE001'
+	placeholderDigit = '\uE010'
+)
+
+// placeholderPattern matches a placeholder and captures its encoded index
+var placeholderPattern = regexp.MustCompile(`\x{E000}([\x{E010}-\x{E019}]+)\x{E001}`)
+
+// restorePattern matches a placeholder and the space after it, if any, which restore
+// drops after a separator
+var restorePattern = regexp.MustCompile(`\x{E000}([\x{E010}-\x{E019}]+)\x{E001}( ?)`)
+
+// isPlaceholderRune reports whether a rune is part of a placeholder
+func isPlaceholderRune(r rune) bool {
+	return r >= placeholderOpen && r <= placeholderDigit+9
+}
+
+// escapePattern matches an escaped modifier such as \(up) or \(hex)
+var escapePattern = regexp.MustCompile(`\\(\([A-Za-z][^()\\]*\))`)
+
+// doubledPattern matches a tag in doubled parentheses, such as ((up)), which writes the
+// tag (up) as text when it names a modifier
+var doubledPattern = regexp.MustCompile(`\((\([A-Za-z][^()\\]*\))\)`)
+
+// protectedTokenPatterns match text that punctuation and quote rules must not touch, most
+// specific first: inline code, URLs, email addresses, file paths, version numbers,
+// math expressions and decimals
+var protectedTokenPatterns = []*regexp.Regexp{
+	regexp.MustCompile("`[^`\n]+`"),
+	regexp.MustCompile(`\b(?:https?|ftp)://[^\s<>"']*[^\s<>"'.,;:!?)\]]`),
+	regexp.MustCompile(`\bwww\.[^\s<>"']*[^\s<>"'.,;:!?)\]]`),
+	regexp.MustCompile(`\b[A-Za-z0-9._%+\-]+@[A-Za-z0-9\-]+(?:\.[A-Za-z0-9\-]+)*\.[A-Za-z]{2,}\b`),
+	regexp.MustCompile(`\b[A-Za-z0-9\-]+(?:\.[A-Za-z0-9\-]+)*\.(?:com|org|net|io|dev|gov|edu|info|co|uk|de|fr|gr|eu)\b`),
+	regexp.MustCompile(`(?:^|[\s(\["'])((?:\.{1,2}|~)?/[\w.\-]+(?:/[\w.\-]*)*|[A-Za-z]:\\[\w.\-\\]+)`),
+	regexp.MustCompile(`\bv?\d+(?:\.\d+){2,}(?:-[\w.]+)?\b|\bv\d+\.\d+\b`),
+	regexp.MustCompile(`-?\d+(?:\.\d+)?(?:\s*[-+*/×÷=<>^]\s*-?\d+(?:\.\d+)?)+`),
+	regexp.MustCompile(`\d+\.\d+`),
+}
+
+// protectedSpans stores the original text of every placeholder
+type protectedSpans struct {
+	values     []string
+	separators map[int]bool // indexes of the values added with addSeparator
+}
+
+// protect replaces every match of re with a placeholder. The replace function decides
+// what text is restored later, e.g. the match without its escape character.
+func (p *protectedSpans) protect(text string, re *regexp.Regexp, replace func(match string) string) string {
+	return re.ReplaceAllStringFunc(text, func(match string) string {
+		return p.add(replace(match))
+	})
+}
+
+// add stores a value and returns its placeholder
+func (p *protectedSpans) add(value string) string {
+	p.values = append(p.values, value)
+	index := strconv.Itoa(len(p.values) - 1)
+
+	var placeholder strings.Builder
+	placeholder.WriteRune(placeholderOpen)
+	for _, digit := range index {
+		placeholder.WriteRune(placeholderDigit + digit - '0')
+	}
+	placeholder.WriteRune(placeholderClose)
+	return placeholder.String()
+}
+
+// addSeparator stores markup that splits the words around it, such as an HTML <br>
+// written between two letters, and returns its placeholder followed by a space. The
+// space keeps the words apart for every rule; restore takes it out again.
+func (p *protectedSpans) addSeparator(value string) string {
+	if p.separators == nil {
+		p.separators = map[int]bool{}
+	}
+	p.separators[len(p.values)] = true
+	return p.add(value) + " "
+}
+
+// restore puts the protected text back in place of its placeholders
+func (p *protectedSpans) restore(text string) string {
+	if len(p.values) == 0 {
+		return text
+	}
+	return restorePattern.ReplaceAllStringFunc(text, func(match string) string {
+		parts := restorePattern.FindStringSubmatch(match)
+		index := 0
+		for _, digit := range parts[1] {
+			index = index*10 + int(digit-placeholderDigit)
+		}
+		if index >= len(p.values) {
+			return match
+		}
+		if p.separators[index] {
+			return p.values[index]
+		}
+		return p.values[index] + parts[2]
+	})
+}
+
+// protectTokens hides URLs, email addresses, file paths, version numbers, math
+// expressions, decimals and inline code from the quote and punctuation rules. They are
+// added to the spans that already hold the escapes, so every placeholder has its own index.
+func protectTokens(text string, spans *protectedSpans) string {
+	for _, re := range protectedTokenPatterns {
+		text = re.ReplaceAllStringFunc(text, func(match string) string {
+			// Leading context such as the space before a path stays outside the placeholder
+			parts := re.FindStringSubmatch(match)
+			token := match
+			if len(parts) > 1 && parts[1] != "" {
+				token = parts[1]
+			}
+			prefix := match[:strings.Index(match, token)]
+			return prefix + spans.add(token)
+		})
+	}
+	return text
+}
+
+// protectEscapes hides escaped modifiers such as \(up) or ((up)) from every rule and drops
+// the backslash or the outer parentheses
+func protectEscapes(text string, spans *protectedSpans) string {
+	text = spans.protect(text, escapePattern, func(match string) string {
+		return match[1:]
+	})
+	return doubledPattern.ReplaceAllStringFunc(text, func(match string) string {
+		if !isModifierTag(match[1 : len(match)-1]) {
+			return match
+		}
+		return spans.add(match[1 : len(match)-1])
+	})
+}
+
+// isModifierTag reports whether a tag such as (up) or (Low|cap, 2) names known modifiers
+func isModifierTag(tag string) bool {
+	parts := modifierTagPattern.FindStringSubmatch(tag)
+	if parts == nil || len(parts[0]) != len(tag) {
+		return false
+	}
+	_, ok := resolveModifierChain(parts[1])
+	return ok
+}
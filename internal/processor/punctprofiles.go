@@ -0,0 +1,133 @@
+// Copyright (c) 2025 Spiros Nikoloudakis
+// Licensed under MIT License - see LICENSE file for details
+
+package processor
+
+import (
+	"fmt"
+	"go-reloaded/internal/pairing"
+	"regexp"
+	"strings"
+)
+
+// Spaces used by punctuation profiles
+const (
+	noBreakSpace       = "\u00a0"
+	narrowNoBreakSpace = "\u202f"
+)
+
+// markSpacing is the space written before and after a punctuation mark; an empty
+// After leaves the following space as written
+type markSpacing struct {
+	Before string
+	After  string
+}
+
+// punctuationProfile describes how a language spaces punctuation and writes quotes
+type punctuationProfile struct {
+	Marks      map[string]markSpacing // spacing around ; : ! ?
+	QuoteOpen  string                 // replaces the opening " of a pair
+	QuoteClose string                 // replaces the closing " of a pair
+	QuoteInner string                 // space just inside the quote marks
+	Ellipsis   string                 // how "..." is written
+	Question   string                 // how "?" is written
+}
+
+// punctuationProfiles lists the supported profiles, keyed by Options.PunctuationProfile
+var punctuationProfiles = map[string]punctuationProfile{
+	"en": {QuoteOpen: `"`, QuoteClose: `"`, Ellipsis: "...", Question: "?"},
+	"fr": {
+		Marks: map[string]markSpacing{
+			";": {Before: narrowNoBreakSpace},
+			"!": {Before: narrowNoBreakSpace},
+			"?": {Before: narrowNoBreakSpace},
+			":": {Before: noBreakSpace},
+		},
+		QuoteOpen: "«", QuoteClose: "»", QuoteInner: noBreakSpace, Ellipsis: "…", Question: "?",
+	},
+	"de": {QuoteOpen: "„", QuoteClose: "“", Ellipsis: "…", Question: "?"},
+	"el": {QuoteOpen: "«", QuoteClose: "»", Ellipsis: "…", Question: ";"},
+}
+
+// IsSupportedPunctuationProfile reports whether a profile name can be used in Options.PunctuationProfile
+func IsSupportedPunctuationProfile(profile string) bool {
+	_, ok := punctuationProfiles[profile]
+	return ok
+}
+
+// currentPunctuationProfile returns the active profile: Options.PunctuationProfile,
+// else the one matching Options.Locale, else English
+func currentPunctuationProfile() (string, punctuationProfile) {
+	for _, name := range []string{activeOptions.PunctuationProfile, activeOptions.Locale} {
+		if profile, ok := punctuationProfiles[name]; ok {
+			return name, profile
+		}
+	}
+	return "en", punctuationProfiles["en"]
+}
+
+// applyQuoteProfile writes double-quote pairs, straight or guillemet, with the active
+// profile's quote marks
+func applyQuoteProfile(text string) string {
+	name, profile := currentPunctuationProfile()
+	if profile.QuoteOpen == `"` && profile.QuoteInner == "" {
+		return text
+	}
+
+	result := pairing.Parse(text).Rewrite(func(r *pairing.Region, inner string) string {
+		if r.Open.Char != '"' && r.Open.Char != '«' {
+			return r.Wrap(inner)
+		}
+		content := strings.Trim(inner, asciiSpace+noBreakSpace+narrowNoBreakSpace)
+		return profile.QuoteOpen + profile.QuoteInner + content + profile.QuoteInner + profile.QuoteClose
+	})
+	if result != text {
+		addQuoteCorrection(fmt.Sprintf("Applied %s quote marks %s…%s", name, profile.QuoteOpen, profile.QuoteClose))
+	}
+	return result
+}
+
+// applyPunctuationProfile spaces ; : ! ? and writes ellipses and question marks the way
+// the active profile does. English spacing has already been applied.
+func applyPunctuationProfile(text string) string {
+	name, profile := currentPunctuationProfile()
+	if name == "en" {
+		return text
+	}
+	result := text
+
+	// An ellipsis style chosen in PunctuationRules takes precedence
+	if profile.Ellipsis != "..." && activeOptions.PunctuationRules.Ellipsis == "" {
+		result = strings.ReplaceAll(result, "...", profile.Ellipsis)
+	}
+	if profile.Question != "?" {
+		result = strings.ReplaceAll(result, "?", profile.Question)
+	}
+
+	if len(profile.Marks) > 0 {
+		var class strings.Builder
+		for mark := range profile.Marks {
+			class.WriteString(regexp.QuoteMeta(mark))
+		}
+		// Only marks that end a word are spaced, so "10:30" and "a?b" are left alone.
+		// A group such as "?!" takes the spacing of its first mark.
+		re := regexp.MustCompile(`(\S?)([` + class.String() + `]+)(\s+|$)`)
+		result = re.ReplaceAllStringFunc(result, func(match string) string {
+			parts := re.FindStringSubmatch(match)
+			before, marks, after := parts[1], parts[2], parts[3]
+			spacing := profile.Marks[marks[:1]]
+			if before != "" && before != noBreakSpace && before != narrowNoBreakSpace {
+				before += spacing.Before
+			}
+			if spacing.After != "" && after != "" {
+				after = spacing.After
+			}
+			return before + marks + after
+		})
+	}
+
+	if result != text {
+		addPunctuationCorrection(fmt.Sprintf("Applied %s punctuation spacing", name))
+	}
+	return result
+}
@@ -0,0 +1,280 @@
+// Copyright (c) 2025 Spiros Nikoloudakis
+// Licensed under MIT License - see LICENSE file for details
+
+package processor
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Ellipsis styles for PunctuationRules.Ellipsis
+const (
+	EllipsisDots = "dots" // ...
+	EllipsisChar = "char" // …
+)
+
+// Ellipsis spacing for PunctuationRules.EllipsisSpacing
+const (
+	EllipsisAttached = "attached" // wait... what
+	EllipsisSpaced   = "spaced"   // wait ... what
+)
+
+// PunctuationRules configures the optional punctuation normalization rules. The zero
+// value turns them all off.
+type PunctuationRules struct {
+	// Abbreviations rewrites spaced or half-dotted abbreviations in their canonical form,
+	// "e. g." → "e.g." and "i.e" → "i.e.", using the built-in list and ExtraAbbreviations
+	Abbreviations bool
+
+	// ExtraAbbreviations adds canonical forms such as "B.Sc." to the built-in list
+	ExtraAbbreviations []string
+
+	// Ellipsis writes every ellipsis, including ". . ." and "....", as EllipsisDots or EllipsisChar
+	Ellipsis string
+
+	// EllipsisSpacing sets the spaces around an ellipsis inside a sentence
+	EllipsisSpacing string
+
+	// Interrobang writes mixed groups such as "!?!?" as "?!" or "!?"
+	Interrobang string
+
+	// MaxRepeat caps runs of ! and ? at this many marks (0 leaves them alone)
+	MaxRepeat int
+}
+
+// punctuationRuleSettings maps the names accepted by ParsePunctuationRules to rule fields
+var punctuationRuleSettings = map[string]func(*PunctuationRules, string) error{
+	"abbreviations": func(r *PunctuationRules, value string) error {
+		r.Abbreviations = true
+		return expectNoValue("abbreviations", value)
+	},
+	"ellipsis": func(r *PunctuationRules, value string) error {
+		r.Ellipsis = value
+		return expectValue("ellipsis", value, EllipsisDots, EllipsisChar)
+	},
+	"ellipsis-spacing": func(r *PunctuationRules, value string) error {
+		r.EllipsisSpacing = value
+		return expectValue("ellipsis-spacing", value, EllipsisAttached, EllipsisSpaced)
+	},
+	"interrobang": func(r *PunctuationRules, value string) error {
+		r.Interrobang = value
+		return expectValue("interrobang", value, "?!", "!?")
+	},
+	"max-repeat": func(r *PunctuationRules, value string) error {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			return fmt.Errorf("max-repeat needs a positive number, got %q", value)
+		}
+		r.MaxRepeat = n
+		return nil
+	},
+}
+
+// expectNoValue rejects a value given to a setting that takes none
+func expectNoValue(name, value string) error {
+	if value != "" {
+		return fmt.Errorf("%s takes no value, got %q", name, value)
+	}
+	return nil
+}
+
+// expectValue checks a setting's value against the allowed ones
+func expectValue(name, value string, allowed ...string) error {
+	for _, a := range allowed {
+		if value == a {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s must be %s, got %q", name, strings.Join(allowed, " or "), value)
+}
+
+// ParsePunctuationRules builds rules from a comma-separated list such as
+// "abbreviations,ellipsis=char,interrobang=?!,max-repeat=1". An empty list turns every rule off.
+func ParsePunctuationRules(list string) (PunctuationRules, error) {
+	var rules PunctuationRules
+	for _, setting := range strings.Split(list, ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(setting), "=")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		set, ok := punctuationRuleSettings[name]
+		if !ok {
+			return rules, fmt.Errorf("unknown punctuation rule %q (use abbreviations, ellipsis, ellipsis-spacing, interrobang or max-repeat)", name)
+		}
+		if err := set(&rules, strings.TrimSpace(value)); err != nil {
+			return rules, err
+		}
+	}
+	return rules, nil
+}
+
+// builtinAbbreviations are the canonical forms used by the abbreviation rule
+var builtinAbbreviations = []string{
+	"e.g.", "i.e.", "a.m.", "p.m.", "U.S.", "U.K.", "U.N.", "E.U.", "N.B.", "Ph.D.", "etc.", "et al.", "vs.",
+}
+
+// abbreviationPattern builds a pattern that matches a canonical abbreviation written with
+// spaces around its dots or with dots missing. Multi-part forms such as "e.g." need at
+// least one dot, so words like "am" and "us" are never matched.
+func abbreviationPattern(canonical string) *regexp.Regexp {
+	parts := strings.Split(strings.TrimSuffix(canonical, "."), ".")
+	for i, part := range parts {
+		parts[i] = strings.ReplaceAll(regexp.QuoteMeta(part), " ", `\s+`)
+	}
+	return regexp.MustCompile(`(?i)\b` + strings.Join(parts, `\s*\.\s*`) + `\b(?:\s*\.)?`)
+}
+
+// normalizeAbbreviations rewrites abbreviations in their canonical form: "e. g." → "e.g."
+func normalizeAbbreviations(text string) string {
+	forms := append(append([]string{}, builtinAbbreviations...), activeOptions.PunctuationRules.ExtraAbbreviations...)
+	for _, canonical := range forms {
+		re := abbreviationPattern(canonical)
+		text = replacePunctuation(text, re, "Abbreviation", func(match string) string {
+			return matchLetterCase(canonical, match)
+		})
+	}
+	return text
+}
+
+// matchLetterCase gives an all-lower-case canonical form the letter case of the text it
+// replaces, so "E. G." becomes "E.G."; canonical forms with capitals are kept as they are
+func matchLetterCase(canonical, original string) string {
+	if canonical != strings.ToLower(canonical) {
+		return canonical
+	}
+	var letters []rune
+	for _, r := range original {
+		if unicode.IsLetter(r) {
+			letters = append(letters, r)
+		}
+	}
+
+	var result strings.Builder
+	i := 0
+	for _, r := range canonical {
+		if unicode.IsLetter(r) && i < len(letters) {
+			if unicode.IsUpper(letters[i]) {
+				r = unicode.ToUpper(r)
+			}
+			i++
+		}
+		result.WriteRune(r)
+	}
+	return result.String()
+}
+
+// ellipsisPattern matches three or more dots, possibly spaced out, or an ellipsis character
+var ellipsisPattern = regexp.MustCompile(`\.(?:\s*\.){2,}|…`)
+
+// normalizeEllipses writes every ellipsis in the configured style
+func normalizeEllipses(text string) string {
+	ellipsis := "..."
+	if activeOptions.PunctuationRules.Ellipsis == EllipsisChar {
+		ellipsis = "…"
+	}
+	return replacePunctuation(text, ellipsisPattern, "Ellipsis", func(match string) string {
+		return ellipsis
+	})
+}
+
+// ellipsisSpacingPattern matches an ellipsis with the spaces around it
+var ellipsisSpacingPattern = regexp.MustCompile(`[ \t]*(\.\.\.|…)[ \t]*`)
+
+// spaceEllipses writes an ellipsis inside a sentence attached to the word before it
+// (wait... what) or with a space on each side (wait ... what). An ellipsis that ends a
+// sentence or comes before a closing mark is always attached: (wait...)
+func spaceEllipses(text string) string {
+	spaced := activeOptions.PunctuationRules.EllipsisSpacing == EllipsisSpaced
+	var result strings.Builder
+	last := 0
+	for _, m := range ellipsisSpacingPattern.FindAllStringSubmatchIndex(text, -1) {
+		start, end := m[0], m[1]
+		before, _ := utf8.DecodeLastRuneInString(text[:start])
+		after, _ := utf8.DecodeRuneInString(text[end:])
+		if !isWordRune(before) {
+			continue
+		}
+
+		ellipsis := text[m[2]:m[3]]
+		replacement := ellipsis + text[m[3]:end]
+		if isWordRune(after) || strings.ContainsRune("([{", after) {
+			replacement = ellipsis + " "
+			if spaced {
+				replacement = " " + ellipsis + " "
+			}
+		}
+		if replacement != text[start:end] {
+			word := text[strings.LastIndexFunc(text[:start], func(r rune) bool { return !isWordRune(r) })+1 : start]
+			next := text[end:]
+			if i := strings.IndexFunc(next, func(r rune) bool { return !isWordRune(r) }); i >= 0 {
+				next = next[:i]
+			}
+			addPunctuationCorrection(fmt.Sprintf("Ellipsis spacing: '%s' → '%s'", word+text[start:end]+next, word+replacement+next))
+		}
+		result.WriteString(text[last:start])
+		result.WriteString(replacement)
+		last = end
+	}
+	result.WriteString(text[last:])
+	return result.String()
+}
+
+// isEllipsis reports whether a group of marks is a single ellipsis
+func isEllipsis(marks string) bool {
+	return marks == "..." || marks == "…"
+}
+
+// markRunPattern matches a run of exclamation and question marks
+var markRunPattern = regexp.MustCompile(`[!?]{2,}`)
+
+// normalizeMarkRuns orders mixed groups such as "!?!?" and caps repeated marks
+func normalizeMarkRuns(text string) string {
+	rules := activeOptions.PunctuationRules
+	if rules.Interrobang != "" {
+		text = replacePunctuation(text, markRunPattern, "Interrobang", func(match string) string {
+			if !strings.Contains(match, "!") || !strings.Contains(match, "?") {
+				return match
+			}
+			return rules.Interrobang
+		})
+	}
+	if rules.MaxRepeat > 0 {
+		text = replacePunctuation(text, markRunPattern, "Repeated marks", func(match string) string {
+			var capped strings.Builder
+			run := 0
+			for i, r := range match {
+				if i > 0 && r != rune(match[i-1]) {
+					run = 0
+				}
+				run++
+				if run <= rules.MaxRepeat {
+					capped.WriteRune(r)
+				}
+			}
+			return capped.String()
+		})
+	}
+	return text
+}
+
+// isWordRune checks if a rune is a letter or digit
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// replacePunctuation replaces every match of re with what fn returns and reports each change
+func replacePunctuation(text string, re *regexp.Regexp, kind string, fn func(match string) string) string {
+	return re.ReplaceAllStringFunc(text, func(match string) string {
+		replaced := fn(match)
+		if replaced != match {
+			addPunctuationCorrection(fmt.Sprintf("%s: '%s' → '%s'", kind, match, replaced))
+		}
+		return replaced
+	})
+}
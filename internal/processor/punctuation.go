@@ -4,6 +4,7 @@
 package processor
 
 import (
+	"fmt"
 	"regexp"
 	"strings"
 )
@@ -21,40 +22,115 @@ func getAndClearPunctuationCorrections() []string {
 	return corrections
 }
 
-// formatPunctuation ensures punctuation spacing consistency for all punctuation marks
+// tightOperatorPattern matches whitespace before any of the given operator characters,
+// with the word before it, or returns nil when there are none. When line breaks are kept
+// only spaces on the same line match.
+func tightOperatorPattern(operators string) *regexp.Regexp {
+	if operators == "" {
+		return nil
+	}
+	var class strings.Builder
+	for _, op := range operators {
+		class.WriteString(regexp.QuoteMeta(string(op)))
+	}
+	space := `\s+`
+	if keepLineBreaks {
+		space = `[ \t]+`
+	}
+	return regexp.MustCompile(`([\p{L}\p{N}]*)` + space + `([` + strings.ReplaceAll(class.String(), "-", `\-`) + `])(\d?)`)
+}
+
+// spaceBeforeMarkPattern matches whitespace before punctuation marks, with the word before it
+var spaceBeforeMarkPattern = regexp.MustCompile(`([\p{L}\p{N}]*)\s+([,.!?;:…]+)`)
+
+// lineSpaceBeforeMarkPattern matches spaces before punctuation marks on the same line, with the word before it
+var lineSpaceBeforeMarkPattern = regexp.MustCompile(`([\p{L}\p{N}]*)[ \t]+([,.!?;:…]+)`)
+
+// extraSpacePattern matches a run of whitespace that is more than a single space
+var extraSpacePattern = regexp.MustCompile(`\s{2,}|[\t\r\n\f\v]`)
+
+// extraLineSpacePattern matches spaces inside a line that are more than a single space
+var extraLineSpacePattern = regexp.MustCompile(`[ \t]{2,}|\t`)
+
+// formatPunctuation ensures punctuation spacing consistency for all punctuation marks.
+// Each change is reported on its own.
 func formatPunctuation(text string) string {
-	original := text
+	rules := activeOptions.PunctuationRules
 	result := text
 	
-	// Remove spaces before basic punctuation: . , ! ? ; :
-	re := regexp.MustCompile(`\s+([,.!?;:])`)
-	result = re.ReplaceAllString(result, "$1")
+	// Canonical abbreviations and ellipses first, so "e. g." and ". . ." are not split up
+	if rules.Abbreviations {
+		result = normalizeAbbreviations(result)
+	}
+	if rules.Ellipsis != "" {
+		result = normalizeEllipses(result)
+	}
 	
-	// Remove spaces before extended punctuation
-	re = regexp.MustCompile(`\s+([\-–—_~\*\+\=\|\\\/%@#\$&])`)
-	result = re.ReplaceAllString(result, "$1")
+	// Remove spaces before punctuation marks, groups such as "!!" and "?!" and ellipses
+	markPattern := spaceBeforeMarkPattern
+	if keepLineBreaks {
+		markPattern = lineSpaceBeforeMarkPattern
+	}
+	result = replacePunctuation(result, markPattern, "Space before punctuation", func(match string) string {
+		parts := markPattern.FindStringSubmatch(match)
+		if rules.EllipsisSpacing != "" && isEllipsis(parts[2]) {
+			return match
+		}
+		return parts[1] + parts[2]
+	})
 	
-	// Handle ellipsis and multiple dots
-	result = strings.ReplaceAll(result, " ...", "...")
-	result = strings.ReplaceAll(result, " …", "…")
+	// Remove spaces before the configured operators, keeping negative numbers like -1 apart
+	if re := tightOperatorPattern(activeOptions.TightOperators); re != nil {
+		result = replacePunctuation(result, re, "Space before operator", func(match string) string {
+			parts := re.FindStringSubmatch(match)
+			if parts[2] == "-" && parts[3] != "" {
+				return match
+			}
+			return parts[1] + parts[2] + parts[3]
+		})
+	}
 	
-	// Handle multiple punctuation groups
-	re = regexp.MustCompile(`\s+([!]{2,})`)
-	result = re.ReplaceAllString(result, "$1")
-	re = regexp.MustCompile(`\s+([?]{2,})`)
-	result = re.ReplaceAllString(result, "$1")
-	re = regexp.MustCompile(`\s+([!?]+)`)
-	result = re.ReplaceAllString(result, "$1")
+	if rules.EllipsisSpacing != "" {
+		result = spaceEllipses(result)
+	}
+	if rules.Interrobang != "" || rules.MaxRepeat > 0 {
+		result = normalizeMarkRuns(result)
+	}
 	
-	// Clean up multiple spaces
-	result = regexp.MustCompile(`\s+`).ReplaceAllString(result, " ")
+	// Clean up multiple spaces, keeping line breaks and the indentation that starts a line
+	// only when they are kept
+	if keepLineBreaks {
+		result = collapseSpaces(result)
+	} else if extra := len(extraSpacePattern.FindAllStringIndex(result, -1)); extra > 0 {
+		result = regexp.MustCompile(`\s+`).ReplaceAllString(result, " ")
+		addPunctuationCorrection(fmt.Sprintf("Collapsed extra whitespace in %d places", extra))
+	}
 	
 	result = strings.TrimSpace(result)
 	
-	// Track if any punctuation corrections were made
-	if original != result {
-		addPunctuationCorrection("Applied punctuation formatting (spacing and grouping)")
+	// Space marks the way the punctuation profile does
+	return applyPunctuationProfile(result)
+}
+
+// collapseSpaces turns runs of spaces and tabs inside each line into one space and removes
+// spaces at the end of lines. Line breaks, blank lines and indentation are kept.
+func collapseSpaces(text string) string {
+	lines := strings.Split(text, "\n")
+	changed := 0
+	for i, line := range lines {
+		body := strings.TrimLeft(line, " \t")
+		indent := line[:len(line)-len(body)]
+		body = extraLineSpacePattern.ReplaceAllString(strings.TrimRight(body, " \t\r"), " ")
+		if body == "" {
+			indent = ""
+		}
+		if indent+body != line {
+			lines[i] = indent + body
+			changed++
+		}
 	}
-	
-	return result
+	if changed > 0 {
+		addPunctuationCorrection(fmt.Sprintf("Collapsed extra whitespace on %d lines", changed))
+	}
+	return strings.Join(lines, "\n")
 }
@@ -3,7 +3,10 @@
 
 package processor
 
-import "regexp"
+import (
+	"go-reloaded/internal/pairing"
+	"strings"
+)
 
 // Global variable to track quote corrections
 var quoteCorrections []string
@@ -18,39 +21,26 @@ func getAndClearQuoteCorrections() []string {
 	return corrections
 }
 
-// formatQuotes cleans spacing around quotes and all paired characters
+// formatQuotes cleans spacing inside quotes and all paired characters
 func formatQuotes(text string) string {
-	original := text
-	result := text
-	
-	// Process single quotes (original behavior)
-	re := regexp.MustCompile(`'\s+([^']+?)\s+'`)
-	result = re.ReplaceAllString(result, "'$1'")
-	
-	// Process double quotes
-	re = regexp.MustCompile(`"\s+([^"]+?)\s+"`)
-	result = re.ReplaceAllString(result, `"$1"`)
-	
-	// Process parentheses
-	re = regexp.MustCompile(`\(\s+([^)]+?)\s+\)`)
-	result = re.ReplaceAllString(result, "($1)")
-	
-	// Process square brackets
-	re = regexp.MustCompile(`\[\s+([^\]]+?)\s+\]`)
-	result = re.ReplaceAllString(result, "[$1]")
-	
-	// Process curly braces
-	re = regexp.MustCompile(`\{\s+([^}]+?)\s+\}`)
-	result = re.ReplaceAllString(result, "{$1}")
-	
-	// Process angle brackets
-	re = regexp.MustCompile(`<\s+([^>]+?)\s+>`)
-	result = re.ReplaceAllString(result, "<$1>")
+	// Convert spans marked with (dq) or (sq) first
+	text = applyQuoteModifiers(text)
+
+	result := pairing.Parse(text).Rewrite(func(r *pairing.Region, inner string) string {
+		if trimmed := strings.Trim(inner, asciiSpace); trimmed != "" {
+			return r.Wrap(trimmed)
+		}
+		return r.Wrap(inner)
+	})
 	
 	// Track if any quote corrections were made
-	if original != result {
+	if result != text {
 		addQuoteCorrection("Applied quote and bracket formatting (spacing normalization)")
 	}
 	
-	return result
-}
\ No newline at end of file
+	// Enforce the house quote style, then write quote marks the way the punctuation profile does
+	return applyQuoteProfile(applyQuoteStyle(result))
+}
+
+// asciiSpace lists the whitespace trimmed inside quotes and brackets; no-break spaces are kept
+const asciiSpace = " \t\n\f\r"
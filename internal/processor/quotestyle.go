@@ -0,0 +1,139 @@
+// Copyright (c) 2025 Spiros Nikoloudakis
+// Licensed under MIT License - see LICENSE file for details
+
+package processor
+
+import (
+	"fmt"
+	"go-reloaded/internal/pairing"
+	"regexp"
+	"strings"
+)
+
+// House quote styles for Options.QuoteStyle; an empty style keeps quotes as written
+const (
+	QuoteStyleDouble = "double" // "outer 'inner "innermost"'"
+	QuoteStyleSingle = "single" // 'outer "inner 'innermost'"'
+)
+
+// quoteModifierNames lists the modifiers that convert a quoted span's delimiters
+const quoteModifierNames = `dq|sq`
+
+// quoteModifierPattern matches (dq) and (sq) with the whitespace before them
+var quoteModifierPattern = regexp.MustCompile(`\s*\((` + quoteModifierNames + `)\)`)
+
+// quoteStyleChars maps modifiers and house styles to the quote character they write
+var quoteStyleChars = map[string]byte{
+	"dq": '"', "sq": '\'',
+	QuoteStyleDouble: '"', QuoteStyleSingle: '\'',
+}
+
+// applyQuoteModifiers converts the quoted span before each (dq) or (sq), or the span that
+// ends with it, to double or single quotes: 'hello' (dq) → "hello". Quotes nested inside
+// the span alternate so they stay distinct from the new outer quotes. A tag without a
+// quoted span is left in place with a warning.
+func applyQuoteModifiers(text string) string {
+	from := 0
+	for {
+		loc := quoteModifierPattern.FindStringSubmatchIndex(text[from:])
+		if loc == nil {
+			return text
+		}
+		start, end := from+loc[0], from+loc[1]
+		modifier := text[from+loc[2] : from+loc[3]]
+		target := taggedQuote(pairing.Parse(text), text, start, end)
+		if target == nil {
+			addQuoteCorrection(fmt.Sprintf("Warning: (%s) has no quoted text to convert - left unchanged", modifier))
+			from = end
+			continue
+		}
+
+		restyled := restyleQuotes(text, target, quoteStyleChars[modifier])
+		text = text[:start] + text[end:]
+		restyled = restyled[:start] + restyled[end:]
+
+		if restyled != text {
+			spanStart, spanEnd := target.Open.Offset, target.Close.Offset+1
+			if end <= target.Close.Offset {
+				spanEnd -= end - start
+			}
+			addQuoteCorrection(fmt.Sprintf("Quote style (%s): %s → %s", modifier, text[spanStart:spanEnd], restyled[spanStart:spanEnd]))
+		}
+		text = restyled
+		from = start
+	}
+}
+
+// taggedQuote finds the straight-quoted span a quote modifier at text[start:end] applies to:
+// the span that ends just before the tag, else the innermost span that ends with the tag
+func taggedQuote(tree *pairing.Tree, text string, start, end int) *pairing.Region {
+	var before, inside *pairing.Region
+	tree.Walk(func(r *pairing.Region) {
+		if !isStraightQuote(r) {
+			return
+		}
+		switch {
+		case r.Close.Offset+1 == start:
+			before = r
+		case r.Open.Offset < start && end <= r.Close.Offset && strings.Trim(text[end:r.Close.Offset], asciiSpace) == "":
+			inside = r
+		}
+	})
+	if before != nil {
+		return before
+	}
+	return inside
+}
+
+// applyQuoteStyle rewrites straight quotes in the house style from Options.QuoteStyle,
+// alternating between double and single quotes at each nesting level
+func applyQuoteStyle(text string) string {
+	char, ok := quoteStyleChars[activeOptions.QuoteStyle]
+	if !ok {
+		return text
+	}
+
+	result := text
+	for _, region := range pairing.Parse(text).Regions {
+		result = restyleQuotes(result, region, char)
+	}
+	if result != text {
+		inner := "double"
+		if char == '"' {
+			inner = "single"
+		}
+		addQuoteCorrection(fmt.Sprintf("Applied %s quote style (%s quotes inside)", activeOptions.QuoteStyle, inner))
+	}
+	return result
+}
+
+// restyleQuotes writes the region's quotes with char and alternates the straight quotes
+// nested inside it. Regions that are not straight quotes are kept but still searched.
+func restyleQuotes(text string, region *pairing.Region, char byte) string {
+	result := []byte(text)
+	var restyle func(r *pairing.Region, char byte)
+	restyle = func(r *pairing.Region, char byte) {
+		if isStraightQuote(r) {
+			result[r.Open.Offset], result[r.Close.Offset] = char, char
+			char = otherQuote(char)
+		}
+		for _, child := range r.Children {
+			restyle(child, char)
+		}
+	}
+	restyle(region, char)
+	return string(result)
+}
+
+// isStraightQuote reports whether a region is delimited by straight ' or " quotes
+func isStraightQuote(r *pairing.Region) bool {
+	return r.Open.Char == '\'' || r.Open.Char == '"'
+}
+
+// otherQuote returns the straight quote used one nesting level further in
+func otherQuote(char byte) byte {
+	if char == '"' {
+		return '\''
+	}
+	return '"'
+}
@@ -0,0 +1,101 @@
+// Copyright (c) 2025 Spiros Nikoloudakis
+// Licensed under MIT License - see LICENSE file for details
+
+package processor
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// romanPattern matches a Roman numeral followed by the (roman) modifier
+var romanPattern = regexp.MustCompile(`\b([IVXLCDMivxlcdm]+)\s*\(roman\)`)
+
+// toRomanPattern matches an integer followed by (toroman) with an optional case argument
+var toRomanPattern = regexp.MustCompile(`\b(\d+)\s*\(toroman(?:,\s*(upper|lower))?\)`)
+
+// MaxRoman is the largest value written with standard Roman numerals
+const MaxRoman = 3999
+
+var romanValues = map[rune]int{
+	'I': 1, 'V': 5, 'X': 10, 'L': 50, 'C': 100, 'D': 500, 'M': 1000,
+}
+
+var romanSymbols = []struct {
+	Value  int
+	Symbol string
+}{
+	{1000, "M"}, {900, "CM"}, {500, "D"}, {400, "CD"},
+	{100, "C"}, {90, "XC"}, {50, "L"}, {40, "XL"},
+	{10, "X"}, {9, "IX"}, {5, "V"}, {4, "IV"}, {1, "I"},
+}
+
+// applyRomanConversions handles (roman) and (toroman) modifiers
+func applyRomanConversions(text string) string {
+	text = romanPattern.ReplaceAllStringFunc(text, func(match string) string {
+		parts := romanPattern.FindStringSubmatch(match)
+		if len(parts) < 2 {
+			return match
+		}
+		value, err := parseRoman(parts[1], activeOptions.StrictRoman)
+		if err != nil {
+			addNumberCorrection(fmt.Sprintf("Warning: %s (roman) %v - left unchanged", parts[1], err))
+			return match
+		}
+		return strconv.Itoa(value)
+	})
+
+	return toRomanPattern.ReplaceAllStringFunc(text, func(match string) string {
+		parts := toRomanPattern.FindStringSubmatch(match)
+		if len(parts) < 3 {
+			return match
+		}
+		value, err := strconv.Atoi(parts[1])
+		if err != nil || value < 1 || value > MaxRoman {
+			addNumberCorrection(fmt.Sprintf("Warning: %s (toroman) is outside 1-%d - left unchanged", parts[1], MaxRoman))
+			return match
+		}
+		numeral := toRoman(value)
+		if parts[2] == "lower" {
+			numeral = strings.ToLower(numeral)
+		}
+		return numeral
+	})
+}
+
+// parseRoman converts a Roman numeral to an integer.
+// In strict mode only canonical numerals are accepted, so IIII and VX are rejected.
+func parseRoman(numeral string, strict bool) (int, error) {
+	upper := strings.ToUpper(numeral)
+	total := 0
+	for i, r := range upper {
+		value := romanValues[r]
+		if i+1 < len(upper) && value < romanValues[rune(upper[i+1])] {
+			total -= value
+		} else {
+			total += value
+		}
+	}
+
+	if total < 1 || total > MaxRoman {
+		return 0, fmt.Errorf("is outside 1-%d", MaxRoman)
+	}
+	if strict && toRoman(total) != upper {
+		return 0, fmt.Errorf("is not a canonical numeral (expected %s)", toRoman(total))
+	}
+	return total, nil
+}
+
+// toRoman converts an integer between 1 and MaxRoman to an upper-case Roman numeral
+func toRoman(value int) string {
+	var numeral strings.Builder
+	for _, s := range romanSymbols {
+		for value >= s.Value {
+			numeral.WriteString(s.Symbol)
+			value -= s.Value
+		}
+	}
+	return numeral.String()
+}
@@ -0,0 +1,204 @@
+// Copyright (c) 2025 Spiros Nikoloudakis
+// Licensed under MIT License - see LICENSE file for details
+
+package processor
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// sentenceTerminatorPattern matches sentence-ending punctuation, any closing quotes or
+// brackets after it and the whitespace that follows
+var sentenceTerminatorPattern = regexp.MustCompile(`(\.\.\.|…|[.!?]+)["'’”)\]]*(\s+)`)
+
+// paragraphBreakPattern matches a blank line, which always ends a sentence
+var paragraphBreakPattern = regexp.MustCompile(`\n[ \t]*\n\s*`)
+
+// abbreviations never end a sentence, even when the next word is capitalized
+var abbreviations = map[string]bool{
+	"dr": true, "mr": true, "mrs": true, "ms": true, "prof": true,
+	"jr": true, "sr": true, "mt": true, "vs": true, "etc": true, "approx": true,
+	"e.g": true, "i.e": true, "cf": true, "fig": true,
+	"vol": true, "pp": true, "inc": true, "ltd": true,
+	"jan": true, "feb": true, "apr": true, "jun": true, "jul": true,
+	"aug": true, "sep": true, "sept": true, "oct": true, "nov": true, "dec": true,
+	"u.s": true, "u.k": true, "u.n": true, "a.m": true, "p.m": true,
+}
+
+// contextAbbreviations are also ordinary words, so they only count as abbreviations when
+// the words around them say so: "No. 5", "p. 12", "Mar. 3", "et al.", "& Co." and "St. Paul".
+// Each check gets the token without its period, the text before it and the text after it.
+var contextAbbreviations = map[string]func(token, before, after string) bool{
+	"no":  func(token, before, after string) bool { return startsWithDigit(after) },
+	"p":   func(token, before, after string) bool { return startsWithDigit(after) },
+	"mar": func(token, before, after string) bool { return startsWithDigit(after) },
+	"al": func(token, before, after string) bool {
+		return strings.EqualFold(lastToken(strings.TrimRight(before, " \t")), "et")
+	},
+	"co": func(token, before, after string) bool {
+		previous := lastToken(strings.TrimRight(before, " \t"))
+		return previous == "&" || (token == "Co" && strings.EqualFold(previous, "and"))
+	},
+	"st": func(token, before, after string) bool { return token == "St" && startsWithUpper(after) },
+}
+
+// initialismPattern matches dotted initials such as U.S. or J.R.R. and single initials such as J.
+var initialismPattern = regexp.MustCompile(`^(?:[A-Za-z]\.)+$`)
+
+// enumeratorPattern matches the rest of a list marker in parentheses, such as the "i)" of
+// "(i)" or the "b)" of "(b)", which keeps its case at the start of a sentence
+var enumeratorPattern = regexp.MustCompile(`^(?:[a-z]|[ivx]+)\)`)
+
+// pronounPattern matches the pronoun "i" and its contractions as a whole word
+var pronounPattern = regexp.MustCompile(`(^|[\s"(\[{])(i)((?:'(?:m|ve|ll|d))?(?:[\s,;:!?"')\]}]|\.(?:\s|$)|$))`)
+
+// SentenceBoundaries returns the byte offset where each sentence after the first begins.
+// A sentence ends at . ! ? or an ellipsis followed by whitespace, after any closing quotes
+// or brackets, and at a blank line. Abbreviations (Dr., e.g., U.S.), initials and decimals
+// do not end a sentence, and an ellipsis only does when the next word is capitalized.
+func SentenceBoundaries(text string) []int {
+	var boundaries []int
+	for _, m := range sentenceTerminatorPattern.FindAllStringSubmatchIndex(text, -1) {
+		terminator := text[m[2]:m[3]]
+		next := m[1]
+		if next >= len(text) {
+			break
+		}
+
+		switch {
+		case terminator == "..." || terminator == "…":
+			if !startsWithUpper(text[next:]) {
+				continue
+			}
+		case terminator == ".":
+			if isAbbreviation(text[:m[3]], text[next:]) {
+				continue
+			}
+		}
+		boundaries = append(boundaries, next)
+	}
+
+	// Blank lines end a sentence even without punctuation
+	for _, m := range paragraphBreakPattern.FindAllStringIndex(text, -1) {
+		if m[1] < len(text) {
+			boundaries = append(boundaries, m[1])
+		}
+	}
+	sort.Ints(boundaries)
+
+	// A blank line after a full stop gives the same boundary twice
+	unique := boundaries[:0]
+	for i, b := range boundaries {
+		if i == 0 || b != boundaries[i-1] {
+			unique = append(unique, b)
+		}
+	}
+	return unique
+}
+
+// lastToken returns the whitespace-separated token that ends the text
+func lastToken(text string) string {
+	return text[strings.LastIndexFunc(text, unicode.IsSpace)+1:]
+}
+
+// isAbbreviation checks whether the token ending the text before a period is an
+// abbreviation or initial, given the text after the period
+func isAbbreviation(before, after string) bool {
+	token := strings.TrimLeft(lastToken(before), "\"'‘“([{")
+	word := strings.TrimSuffix(token, ".")
+	if check, ok := contextAbbreviations[strings.ToLower(word)]; ok {
+		return check(word, before[:len(before)-len(token)], after)
+	}
+	if initialismPattern.MatchString(token) {
+		return true
+	}
+	return abbreviations[strings.ToLower(word)]
+}
+
+// startsWithDigit reports whether the text starts with a digit
+func startsWithDigit(text string) bool {
+	return text != "" && text[0] >= '0' && text[0] <= '9'
+}
+
+// startsWithUpper reports whether the first letter of the text, after opening quotes
+// and brackets, is upper-case
+func startsWithUpper(text string) bool {
+	text = strings.TrimLeft(text, "\"'‘“([{")
+	r, _ := utf8.DecodeRuneInString(text)
+	return unicode.IsUpper(r)
+}
+
+// lastSentenceStart returns the offset where the sentence that ends the text begins
+func lastSentenceStart(text string) int {
+	if boundaries := SentenceBoundaries(text); len(boundaries) > 0 {
+		return boundaries[len(boundaries)-1]
+	}
+	return 0
+}
+
+// firstSentenceEnd returns the offset just after the first sentence of the text
+func firstSentenceEnd(text string) int {
+	if boundaries := SentenceBoundaries(text); len(boundaries) > 0 {
+		return boundaries[0]
+	}
+	return len(text)
+}
+
+// Global variable holding the processed text before the current region, so the first
+// word of a region is only capitalized when it starts a sentence
+var regionContext string
+
+// endsSentence reports whether the text is empty or ends with a finished sentence
+func endsSentence(text string) bool {
+	text = strings.TrimRight(text, " \t\r\n")
+	if text == "" {
+		return true
+	}
+	return lastSentenceStart(text+" x") == len(text)+1
+}
+
+// capitalizeSentences upper-cases the first letter of every sentence and the pronoun "i".
+// List markers such as (i) or (b) keep their case.
+func capitalizeSentences(text string) string {
+	starts := SentenceBoundaries(text)
+	if endsSentence(regionContext) {
+		starts = append([]int{0}, starts...)
+	}
+
+	result := []byte(text)
+	offset := 0
+	for _, start := range starts {
+		rest := text[start:]
+		skip := len(rest) - len(strings.TrimLeft(rest, " \t\r\n\"'‘“([{"))
+		r, size := utf8.DecodeRuneInString(rest[skip:])
+		if !unicode.IsLower(r) || isEnumerator(rest, skip) {
+			continue
+		}
+		pos := start + skip + offset
+		upper := string(unicode.ToUpper(r))
+		word := strings.Fields(rest[skip:])[0]
+		caseCorrections = append(caseCorrections, fmt.Sprintf("Sentence start: '%s' → '%s'", word, upper+word[size:]))
+		result = append(result[:pos], append([]byte(upper), result[pos+size:]...)...)
+		offset += len(upper) - size
+	}
+
+	return pronounPattern.ReplaceAllStringFunc(string(result), func(match string) string {
+		parts := pronounPattern.FindStringSubmatch(match)
+		if parts[1] == "(" && strings.HasPrefix(parts[3], ")") {
+			return match // the list marker (i)
+		}
+		caseCorrections = append(caseCorrections, "Pronoun: 'i' → 'I'")
+		return parts[1] + "I" + parts[3]
+	})
+}
+
+// isEnumerator reports whether text[i:] is the letter or roman numeral of a list marker
+// in parentheses, such as (i) or (b)
+func isEnumerator(text string, i int) bool {
+	return i > 0 && text[i-1] == '(' && enumeratorPattern.MatchString(text[i:])
+}
@@ -0,0 +1,57 @@
+// Copyright (c) 2025 Spiros Nikoloudakis
+// Licensed under MIT License - see LICENSE file for details
+
+package processor
+
+import (
+	"go-reloaded/internal/source"
+	"strings"
+)
+
+// parseSource splits source code into code, comments and string literals with the lexer
+// for Options.Format
+func parseSource(text string) *source.Document {
+	switch activeOptions.Format {
+	case FormatHash:
+		return source.ParseComments(text, source.HashComments)
+	case FormatSlash:
+		return source.ParseComments(text, source.SlashComments)
+	}
+	return source.ParseGo(text, activeOptions.StringLiterals)
+}
+
+// processSource runs the pipeline over the comments, and string literals if enabled, of
+// source code. Each comment paragraph is processed on its own with its markers and
+// indentation kept as written, so code is never touched. A string literal whose result
+// would no longer be valid, because it now holds its quote or a line break, is kept.
+func processSource(doc *source.Document) string {
+	prose := &proseProcessor{}
+	return doc.Rewrite(func(b *source.Block) string {
+		var pieces []proseSpan
+		for _, inline := range b.Inlines {
+			pieces = append(pieces, proseSpan{text: inline.Text, prose: inline.Prose})
+		}
+		processed := prose.process(pieces)
+		if b.Kind == source.String && !validLiteral(processed, b.Prefixes[0][:1]) {
+			return b.Content
+		}
+		return processed
+	})
+}
+
+// validLiteral reports whether text can stand between the given quotes: a raw string
+// cannot hold a backtick, an interpreted one an unescaped quote or a line break
+func validLiteral(text, quote string) bool {
+	if quote == "`" {
+		return !strings.Contains(text, "`")
+	}
+	for i := 0; i < len(text); i++ {
+		switch {
+		case text[i] == '\\':
+			i++
+		case text[i] == '\n' || text[i] == quote[0]:
+			return false
+		}
+	}
+	return true
+}
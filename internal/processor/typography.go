@@ -0,0 +1,137 @@
+// Copyright (c) 2025 Spiros Nikoloudakis
+// Licensed under MIT License - see LICENSE file for details
+
+package processor
+
+import (
+	"go-reloaded/internal/pairing"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Typography modes for Options.Typography; an empty mode leaves the rule off
+const (
+	TypographySmart = "smart"
+	TypographyASCII = "ascii"
+)
+
+// Dash spacing styles for Options.DashSpacing; an empty style keeps the spaces as written
+const (
+	DashClosed = "closed"
+	DashSpaced = "spaced"
+)
+
+// dashPattern matches -- and --- with the spaces around them
+var dashPattern = regexp.MustCompile(`( *)(-{2,3})( *)`)
+
+// asciiReplacer turns typographic characters back into their ASCII spellings.
+// Guillemets with their inner no-break spaces come first so they become plain quotes.
+var asciiReplacer = strings.NewReplacer(
+	"«"+noBreakSpace, `"`, noBreakSpace+"»", `"`,
+	"‘", "'", "’", "'", "“", `"`, "”", `"`, "„", `"`, "«", `"`, "»", `"`,
+	"—", "---", "–", "--", "…", "...",
+	noBreakSpace, " ", narrowNoBreakSpace, " ",
+)
+
+// applyTypography converts quotes, apostrophes, dashes and ellipses according to Options.Typography
+func applyTypography(text string) string {
+	var result string
+	switch activeOptions.Typography {
+	case TypographySmart:
+		result = smartDashes(smartQuotes(text))
+		result = strings.ReplaceAll(result, "...", "…")
+		if result != text {
+			addPunctuationCorrection("Applied smart typography (curly quotes, apostrophes, dashes and ellipses)")
+		}
+	case TypographyASCII:
+		result = asciiReplacer.Replace(text)
+		if result != text {
+			addPunctuationCorrection("Converted typographic quotes, dashes and ellipses to ASCII")
+		}
+	default:
+		return text
+	}
+	return result
+}
+
+// smartQuotes replaces straight quotes with curly ones, pairing them the same way
+// the validator does so contractions and elisions become apostrophes rather than quotes
+func smartQuotes(text string) string {
+	var result strings.Builder
+	last := 0
+	for _, mark := range pairing.Parse(text).Marks {
+		if mark.Char != '\'' && mark.Char != '"' {
+			continue
+		}
+		result.WriteString(text[last:mark.Offset])
+		result.WriteString(curlyQuote(text, mark))
+		last = mark.Offset + 1
+	}
+	result.WriteString(text[last:])
+	return result.String()
+}
+
+// curlyQuote picks the curly form of a straight quote from its role and context
+func curlyQuote(text string, mark pairing.Mark) string {
+	single := mark.Char == '\''
+	switch {
+	case mark.Role == pairing.Apostrophe:
+		return "’"
+	case mark.Role == pairing.Open && single:
+		return "‘"
+	case mark.Role == pairing.Open:
+		return "“"
+	case mark.Role == pairing.Close && single:
+		return "’"
+	case mark.Role == pairing.Close:
+		return "”"
+	case single:
+		return "’"
+	}
+
+	// An unpaired " opens when it starts a word and closes otherwise
+	previous, _ := utf8.DecodeLastRuneInString(text[:mark.Offset])
+	if mark.Offset == 0 || unicode.IsSpace(previous) || strings.ContainsRune("([{", previous) {
+		return "“"
+	}
+	return "”"
+}
+
+// smartDashes turns -- into an en dash and --- into an em dash, spaced according to
+// Options.DashSpacing. Command-line flags such as --verbose and longer runs are left alone.
+func smartDashes(text string) string {
+	matches := dashPattern.FindAllStringSubmatchIndex(text, -1)
+	for i := len(matches) - 1; i >= 0; i-- {
+		m := matches[i]
+		before, _ := utf8.DecodeLastRuneInString(text[:m[0]])
+		after, _ := utf8.DecodeRuneInString(text[m[1]:])
+		if before == '-' || after == '-' {
+			continue
+		}
+		leading, trailing := text[m[2]:m[3]], text[m[6]:m[7]]
+		if (leading != "" || m[0] == 0) && trailing == "" && unicode.IsLetter(after) {
+			continue
+		}
+
+		dash := "–"
+		if m[5]-m[4] == 3 {
+			dash = "—"
+		}
+		switch activeOptions.DashSpacing {
+		case DashClosed:
+			leading, trailing = "", ""
+		case DashSpaced:
+			leading, trailing = " ", " "
+			if m[0] == 0 {
+				leading = ""
+			}
+			if m[1] == len(text) {
+				trailing = ""
+			}
+		}
+		text = text[:m[0]] + leading + dash + trailing + text[m[1]:]
+	}
+	return text
+}
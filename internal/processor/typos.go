@@ -0,0 +1,152 @@
+// Copyright (c) 2025 Spiros Nikoloudakis
+// Licensed under MIT License - see LICENSE file for details
+
+package processor
+
+import (
+	"fmt"
+	"go-reloaded/internal/pairing"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Typo rule modes for Options.Typos; an empty mode leaves the rule off
+const (
+	TyposFix   = "fix"
+	TyposCheck = "check"
+)
+
+// repeatAllowlist holds words that are often repeated on purpose, as in "had had" or "that that"
+var repeatAllowlist = map[string]bool{
+	"had": true, "that": true, "bye": true, "no": true, "very": true, "so": true,
+}
+
+// typoWordPattern matches a word, including a contraction such as don't
+var typoWordPattern = regexp.MustCompile(`[A-Za-z]+(?:'[A-Za-z]+)?`)
+
+// doubledPunctuationPattern matches ",," and ";;" and a pair of periods after a word
+var doubledPunctuationPattern = regexp.MustCompile(`,{2,}|;{2,}|[A-Za-z](\.\.)(?:\s|$)`)
+
+// splitContractionPattern matches a contraction typed with a stray space, like "don 't" or "do n't"
+var splitContractionPattern = regexp.MustCompile(`([A-Za-z]+) +(n't|'(?:t|s|re|ve|ll|d|m))\b`)
+
+// typo is a likely typing mistake found by findTypos
+type typo struct {
+	start, end int
+	kind       string
+	original   string
+	fixed      string
+}
+
+// Global variable to track typo corrections
+var typoCorrections []string
+
+func addTypoCorrection(correction string) {
+	typoCorrections = append(typoCorrections, correction)
+}
+
+func getAndClearTypoCorrections() []string {
+	corrections := typoCorrections
+	typoCorrections = nil
+	return corrections
+}
+
+// correctTypos fixes or reports repeated words, doubled punctuation and split contractions,
+// depending on Options.Typos
+func correctTypos(text string) string {
+	typos := findTypos(text)
+	if activeOptions.Typos != TyposFix {
+		for _, t := range typos {
+			addTypoCorrection(fmt.Sprintf("%s: '%s' (check only, left unchanged)", t.kind, t.original))
+		}
+		return text
+	}
+
+	for i := len(typos) - 1; i >= 0; i-- {
+		t := typos[i]
+		text = text[:t.start] + t.fixed + text[t.end:]
+	}
+	for _, t := range typos {
+		addTypoCorrection(fmt.Sprintf("%s: '%s' → '%s'", t.kind, t.original, t.fixed))
+	}
+	return text
+}
+
+// findTypos returns the typos in the text in order, without overlaps
+func findTypos(text string) []typo {
+	var typos []typo
+
+	// Repeated words: a run of the same word separated only by spaces
+	words := typoWordPattern.FindAllStringIndex(text, -1)
+	for i := 0; i < len(words); {
+		j := i
+		for j+1 < len(words) && isRepeat(text, words[j], words[j+1]) {
+			j++
+		}
+		if j > i {
+			start, end := words[i][0], words[j][1]
+			typos = append(typos, typo{start, end, "Repeated word", text[start:end], text[words[i][0]:words[i][1]]})
+		}
+		i = j + 1
+	}
+
+	for _, m := range doubledPunctuationPattern.FindAllStringSubmatchIndex(text, -1) {
+		start, end := m[0], m[1]
+		if m[2] >= 0 {
+			start, end = m[2], m[3]
+		}
+		typos = append(typos, typo{start, end, "Doubled punctuation", text[start:end], text[start : start+1]})
+	}
+
+	var marks []pairing.Mark
+	for _, m := range splitContractionPattern.FindAllStringSubmatchIndex(text, -1) {
+		// A quoted letter such as 'd' in "the 'd' key" is not a contraction
+		if marks == nil {
+			marks = pairing.Parse(text).Marks
+		}
+		quote := m[4] + strings.IndexByte(text[m[4]:m[5]], '\'')
+		i := sort.Search(len(marks), func(i int) bool { return marks[i].Offset >= quote })
+		if i < len(marks) && marks[i].Offset == quote && marks[i].Role != pairing.Apostrophe {
+			continue
+		}
+		original := text[m[0]:m[1]]
+		typos = append(typos, typo{m[0], m[1], "Split contraction", original, text[m[2]:m[3]] + text[m[4]:m[5]]})
+	}
+
+	sort.Slice(typos, func(a, b int) bool { return typos[a].start < typos[b].start })
+	var result []typo
+	for _, t := range typos {
+		if n := len(result); n > 0 && t.start < result[n-1].end {
+			continue
+		}
+		result = append(result, t)
+	}
+	return result
+}
+
+// isRepeat checks whether two neighbouring words are an accidental repetition
+func isRepeat(text string, first, second []int) bool {
+	gap := text[first[1]:second[0]]
+	if gap == "" || strings.Trim(gap, " \t") != "" {
+		return false
+	}
+	word := text[first[0]:first[1]]
+	if !strings.EqualFold(word, text[second[0]:second[1]]) {
+		return false
+	}
+	return !isAllowedRepeat(word)
+}
+
+// isAllowedRepeat checks the built-in allowlist and Options.RepeatAllowlist
+func isAllowedRepeat(word string) bool {
+	if repeatAllowlist[strings.ToLower(word)] {
+		return true
+	}
+	for _, allowed := range activeOptions.RepeatAllowlist {
+		if strings.EqualFold(allowed, word) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,184 @@
+// Copyright (c) 2025 Spiros Nikoloudakis
+// Licensed under MIT License - see LICENSE file for details
+
+package processor
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// WordCountPolicy decides which tokens a count such as (up, 3) counts as words.
+// The zero value is the original behaviour: only tokens with letters count, a
+// compound counts once and counts may reach into earlier sentences and lines.
+type WordCountPolicy struct {
+	// CountNumbers counts tokens made of digits, such as 42 or 3.5
+	CountNumbers bool
+
+	// SplitCompounds counts each part of a hyphenated or apostrophe word, so
+	// "well-known" counts as two words. A compound is never split by a count.
+	SplitCompounds bool
+
+	// CountPunctuation counts tokens without letters or digits, such as "-" or "..."
+	CountPunctuation bool
+
+	// StopAtSentence keeps counts inside the sentence that holds the modifier
+	StopAtSentence bool
+
+	// StopAtLine keeps counts inside the line that holds the modifier
+	StopAtLine bool
+}
+
+// wordCountSettings maps the names accepted by ParseWordCountPolicy to policy fields
+var wordCountSettings = map[string]func(*WordCountPolicy){
+	"numbers":     func(p *WordCountPolicy) { p.CountNumbers = true },
+	"compounds":   func(p *WordCountPolicy) { p.SplitCompounds = true },
+	"punctuation": func(p *WordCountPolicy) { p.CountPunctuation = true },
+	"sentence":    func(p *WordCountPolicy) { p.StopAtSentence = true },
+	"line":        func(p *WordCountPolicy) { p.StopAtLine = true },
+}
+
+// ParseWordCountPolicy builds a policy from a comma-separated list such as
+// "numbers,compounds,sentence". An empty list gives the default policy.
+func ParseWordCountPolicy(list string) (WordCountPolicy, error) {
+	var policy WordCountPolicy
+	for _, name := range strings.Split(list, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		set, ok := wordCountSettings[name]
+		if !ok {
+			return policy, fmt.Errorf("unknown word counting setting %q (use numbers, compounds, punctuation, sentence or line)", name)
+		}
+		set(&policy)
+	}
+	return policy, nil
+}
+
+// String describes the policy for the transformation report
+func (p WordCountPolicy) String() string {
+	if p == (WordCountPolicy{}) {
+		return "numbers excluded"
+	}
+
+	var parts []string
+	if p.CountNumbers {
+		parts = append(parts, "numbers counted")
+	} else {
+		parts = append(parts, "numbers excluded")
+	}
+	if p.SplitCompounds {
+		parts = append(parts, "compound parts counted separately")
+	}
+	if p.CountPunctuation {
+		parts = append(parts, "punctuation counted")
+	}
+	if p.StopAtSentence {
+		parts = append(parts, "within the sentence")
+	}
+	if p.StopAtLine {
+		parts = append(parts, "within the line")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// wordWeight returns how many words a token counts as under the active policy
+func wordWeight(token string) int {
+	if caseTagPattern.MatchString(token) {
+		return 0
+	}
+	policy := activeOptions.WordCount
+
+	hasLetter, hasDigit := false, false
+	for _, r := range token {
+		switch {
+		case unicode.IsLetter(r):
+			hasLetter = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		}
+	}
+
+	switch {
+	case hasLetter && policy.SplitCompounds:
+		return len(strings.FieldsFunc(token, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		}))
+	case hasLetter:
+		return 1
+	case hasDigit && policy.CountNumbers:
+		return 1
+	case !hasDigit && policy.CountPunctuation:
+		return 1
+	}
+	return 0
+}
+
+// scopeStart returns the index of the first word of the sentence or line that ends the list
+func (l wordList) scopeStart(scope string) int {
+	if scope == "line" {
+		for i := len(l.words); i > 0; i-- {
+			if strings.Contains(l.gaps[i], "\n") {
+				return i
+			}
+		}
+		return 0
+	}
+	// A full stop at the very end does not start a new sentence yet
+	for i := len(l.words) - 1; i > 0; i-- {
+		if l.sentenceBreakBefore(i) {
+			return i
+		}
+	}
+	return 0
+}
+
+// sentenceBreakBefore reports whether a sentence ends right before word i, by the rules of
+// SentenceBoundaries. Only the word before it and the gap between them decide.
+func (l wordList) sentenceBreakBefore(i int) bool {
+	if i == 0 {
+		return paragraphBreakPattern.MatchString(l.gaps[0])
+	}
+	text := l.words[i-1] + l.gaps[i] + l.words[i]
+	start := len(text) - len(l.words[i])
+	for _, boundary := range SentenceBoundaries(text) {
+		if boundary == start {
+			return true
+		}
+	}
+	return false
+}
+
+// countingFloor returns the index of the first word before the modifier that a count may reach
+func (l wordList) countingFloor() int {
+	policy := activeOptions.WordCount
+	start := 0
+	if policy.StopAtSentence {
+		start = l.scopeStart("sentence")
+	}
+	if policy.StopAtLine {
+		start = max(start, l.scopeStart("line"))
+	}
+	return start
+}
+
+// countingCeiling returns how many words after a forward modifier a count may reach
+func (l wordList) countingCeiling() int {
+	policy := activeOptions.WordCount
+	for i := range l.words {
+		if (policy.StopAtLine && strings.Contains(l.gaps[i], "\n")) || (policy.StopAtSentence && l.sentenceBreakBefore(i)) {
+			return i
+		}
+	}
+	return len(l.words)
+}
+
+// max returns the maximum of two integers
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,250 @@
+// Copyright (c) 2025 Spiros Nikoloudakis
+// Licensed under MIT License - see LICENSE file for details
+
+package processor
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Wrap widths
+const (
+	DefaultWrapWidth = 72 // used by a (wrap) tag without a width when Options.Wrap is not set
+	MinWrapWidth     = 10 // narrower widths are raised to this
+)
+
+// wrapTagPattern matches a (wrap) or (wrap, N) tag
+var wrapTagPattern = regexp.MustCompile(`\(wrap(?:,\s*(\d+))?\)`)
+
+// linePrefixPattern matches what starts a line before its text: indentation, quote
+// markers such as "> >", and a list marker such as "-", "*", "+", "•", "1." or "2)"
+var linePrefixPattern = regexp.MustCompile(`^([ \t]*(?:>[ \t]?)*)((?:[-*+•]|\d+[.)])[ \t]+)?`)
+
+// Global variable set by each pipeline run: line breaks and indentation are only kept when
+// paragraphs are reflowed or a structured document is processed. Plain text is otherwise
+// flattened to single spaces, as it always has been.
+var keepLineBreaks bool
+
+// wrapTags maps the placeholder of each (wrap, N) tag to its width
+type wrapTags map[string]int
+
+// protectWrapTags hides (wrap, N) tags from every rule until the paragraphs are reflowed
+func protectWrapTags(text string, spans *protectedSpans) (string, wrapTags) {
+	tags := wrapTags{}
+	text = wrapTagPattern.ReplaceAllStringFunc(text, func(match string) string {
+		width := activeOptions.Wrap
+		if width == 0 {
+			width = DefaultWrapWidth
+		}
+		if parts := wrapTagPattern.FindStringSubmatch(match); parts[1] != "" {
+			width, _ = strconv.Atoi(parts[1])
+		}
+		// (wrap, 0) is a narrow width too, not a way to turn wrapping off
+		if width < MinWrapWidth {
+			width = MinWrapWidth
+		}
+		placeholder := spans.add("")
+		tags[placeholder] = width
+		return placeholder
+	})
+	return text, tags
+}
+
+// wrapParagraphs reflows every paragraph to Options.Wrap columns, or to the width of the
+// (wrap, N) tag inside it, and removes the tags. measure returns the printed width of a word.
+func wrapParagraphs(text string, tags wrapTags, measure func(string) int) string {
+	if activeOptions.Wrap == 0 && len(tags) == 0 {
+		return text
+	}
+
+	var result []string
+	var paragraph []string
+	flush := func() {
+		if len(paragraph) > 0 {
+			result = append(result, wrapParagraph(paragraph, tags, measure)...)
+			paragraph = nil
+		}
+	}
+	for _, line := range strings.Split(text, "\n") {
+		if strings.TrimSpace(line) == "" {
+			flush()
+			result = append(result, line)
+			continue
+		}
+		paragraph = append(paragraph, line)
+	}
+	flush()
+	return strings.Join(result, "\n")
+}
+
+// wrapItem is a list item or a run of lines that share a prefix, reflowed as one
+type wrapItem struct {
+	first        string // prefix of the first line, with any list marker
+	continuation string // prefix of the lines after it
+	words        []string
+}
+
+// wrapParagraph reflows the lines of one paragraph
+func wrapParagraph(lines []string, tags wrapTags, measure func(string) int) []string {
+	lines, width := takeWrapTags(lines, tags)
+	if width == 0 || !ruleEnabled("wrap") {
+		return lines
+	}
+	if width < MinWrapWidth {
+		width = MinWrapWidth
+	}
+
+	var items []*wrapItem
+	for _, line := range lines {
+		parts := linePrefixPattern.FindStringSubmatch(line)
+		lead, marker := parts[1], parts[2]
+		words := strings.Fields(line[len(parts[0]):])
+
+		current := len(items) - 1
+		if current >= 0 && marker == "" && lead == items[current].continuation {
+			items[current].words = append(items[current].words, words...)
+			continue
+		}
+		continuation := lead
+		if marker != "" {
+			continuation += strings.Repeat(" ", measure(marker))
+		}
+		items = append(items, &wrapItem{first: lead + marker, continuation: continuation, words: words})
+	}
+
+	var result []string
+	for _, item := range items {
+		result = append(result, item.lines(width, measure)...)
+	}
+	if strings.Join(result, "\n") != strings.Join(lines, "\n") {
+		addPunctuationCorrection(fmt.Sprintf("Wrapped a paragraph of %d lines at %d columns", len(result), width))
+	}
+	return result
+}
+
+// takeWrapTags removes the (wrap, N) tags from a paragraph and returns the width to wrap
+// it at: the last tag's width, else Options.Wrap. A line that only held a tag is dropped.
+func takeWrapTags(lines []string, tags wrapTags) ([]string, int) {
+	width := activeOptions.Wrap
+	var kept []string
+	for _, line := range lines {
+		original := line
+		for _, placeholder := range placeholderPattern.FindAllString(line, -1) {
+			if tagWidth, ok := tags[placeholder]; ok {
+				line = strings.Replace(line, " "+placeholder, "", 1)
+				line = strings.Replace(line, placeholder, "", 1)
+				width = tagWidth
+			}
+		}
+		if line != original && strings.TrimSpace(line) == "" {
+			continue
+		}
+		kept = append(kept, line)
+	}
+	return kept, width
+}
+
+// lines fills the item's words into lines of at most width columns. A word wider than
+// the line, such as a long URL, gets a line of its own and is never broken.
+func (item *wrapItem) lines(width int, measure func(string) int) []string {
+	if len(item.words) == 0 {
+		return []string{strings.TrimRight(item.first, " \t")}
+	}
+
+	var lines [][]string
+	var line []string
+	used := 0
+	prefix := item.first
+	for _, word := range item.words {
+		available := width - measure(prefix)
+		if len(line) > 0 && used+1+measure(word) > available {
+			lines = append(lines, line)
+			line, used, prefix = nil, 0, item.continuation
+		}
+		if len(line) > 0 {
+			used++
+		}
+		line = append(line, word)
+		used += measure(word)
+	}
+	lines = append(lines, line)
+
+	result := make([]string, len(lines))
+	for i, words := range lines {
+		prefix := item.continuation
+		if i == 0 {
+			prefix = item.first
+		}
+		if activeOptions.Justify && i < len(lines)-1 {
+			result[i] = prefix + justifyLine(words, width-measure(prefix), measure)
+		} else {
+			result[i] = prefix + strings.Join(words, " ")
+		}
+	}
+	return result
+}
+
+// justifyLine spreads the words over the width by widening the gaps between them,
+// the leftmost gaps first
+func justifyLine(words []string, width int, measure func(string) int) string {
+	if len(words) < 2 {
+		return strings.Join(words, " ")
+	}
+	used := 0
+	for _, word := range words {
+		used += measure(word)
+	}
+	gaps := len(words) - 1
+	spaces := width - used
+	if spaces < gaps {
+		return strings.Join(words, " ")
+	}
+
+	var result strings.Builder
+	for i, word := range words {
+		result.WriteString(word)
+		if i < gaps {
+			n := spaces / gaps
+			if i < spaces%gaps {
+				n++
+			}
+			result.WriteString(strings.Repeat(" ", n))
+		}
+	}
+	return result.String()
+}
+
+// displayWidth returns how many columns the text takes in a terminal
+func displayWidth(text string) int {
+	width := 0
+	for _, r := range text {
+		width += runeWidth(r)
+	}
+	return width
+}
+
+// wideRanges lists the East Asian wide and fullwidth characters and emoji, which take two columns
+var wideRanges = [][2]rune{
+	{0x1100, 0x115F}, {0x2E80, 0x303E}, {0x3041, 0x33FF}, {0x3400, 0x4DBF},
+	{0x4E00, 0x9FFF}, {0xA000, 0xA4CF}, {0xAC00, 0xD7A3}, {0xF900, 0xFAFF},
+	{0xFE30, 0xFE4F}, {0xFF00, 0xFF60}, {0xFFE0, 0xFFE6}, {0x1F300, 0x1F64F},
+	{0x1F900, 0x1F9FF}, {0x20000, 0x2FFFD}, {0x30000, 0x3FFFD},
+}
+
+// runeWidth returns how many columns a rune takes: 0 for combining marks and invisible
+// format characters, 2 for wide characters and 1 otherwise
+func runeWidth(r rune) int {
+	if unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf) {
+		return 0
+	}
+	for _, wide := range wideRanges {
+		if r >= wide[0] && r <= wide[1] {
+			return 2
+		}
+	}
+	return 1
+}
@@ -0,0 +1,209 @@
+// Copyright (c) 2025 Spiros Nikoloudakis
+// Licensed under MIT License - see LICENSE file for details
+
+package source
+
+import (
+	"go/scanner"
+	"go/token"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// escapePattern matches an escape sequence in an interpreted Go string literal
+var escapePattern = regexp.MustCompile(`\\(?:[0-7]{3}|x[0-9a-fA-F]{2}|u[0-9a-fA-F]{4}|U[0-9a-fA-F]{8}|.)`)
+
+// formatVerbPattern matches a fmt verb such as %s, %-8d or %[1]q
+var formatVerbPattern = regexp.MustCompile(`%(?:\[\d+\])?[-+# 0]*(?:\d+|\*)?(?:\.(?:\d+|\*)?)?[a-zA-Z%]`)
+
+// structTagPattern matches a struct tag such as `json:"name,omitempty" db:"name"`
+var structTagPattern = regexp.MustCompile(`^(?:\w+:"[^"]*"\s*)+$`)
+
+// goToken is a token found by go/scanner, with its byte offset
+type goToken struct {
+	offset int
+	tok    token.Token
+	lit    string
+}
+
+// ParseGo splits Go source into code, comments and, when literals is set, string
+// literals that read as prose. It uses go/scanner, so comment markers inside strings
+// and strings inside comments are told apart exactly as the compiler does.
+//
+// Directives such as //go:build and //nolint, which have no space after the //, lines
+// indented as code examples, cgo preambles, import paths and struct tags are kept as
+// written. The name a doc comment starts with, as in "// Parse splits", is kept too.
+func ParseGo(text string, literals bool) *Document {
+	fset := token.NewFileSet()
+	file := fset.AddFile("", fset.Base(), len(text))
+	var s scanner.Scanner
+	s.Init(file, []byte(text), nil, scanner.ScanComments)
+
+	var tokens []goToken
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+		tokens = append(tokens, goToken{offset: file.Offset(pos), tok: tok, lit: lit})
+	}
+
+	var spans []span
+	importing, grouped := false, false // inside an import declaration, and a parenthesized one
+	for i, t := range tokens {
+		switch t.tok {
+		case token.IMPORT:
+			importing = true
+			j := nextSignificant(tokens, i)
+			grouped = j >= 0 && tokens[j].tok == token.LPAREN
+		case token.RPAREN:
+			importing = false
+		case token.COMMENT:
+			if isCgoPreamble(tokens, i) {
+				continue
+			}
+			c := commentSpan(text, t.offset)
+			c.name = documentedName(tokens, i)
+			spans = append(spans, c)
+		case token.STRING:
+			if importing {
+				importing = grouped
+				continue
+			}
+			if c, ok := stringSpan(text, t.offset, t.lit); ok && literals {
+				spans = append(spans, c)
+			}
+		}
+	}
+
+	b := &builder{doc: &Document{Text: text}, text: text, preformatted: true, needsSpace: true}
+	return b.build(spans)
+}
+
+// commentSpan returns the span of the // or /* */ comment at offset. A line comment ends
+// before its line ending.
+func commentSpan(text string, offset int) span {
+	if strings.HasPrefix(text[offset:], "//") {
+		return lineSpan(text, offset, "//")
+	}
+	end := strings.Index(text[offset+2:], "*/")
+	if end < 0 {
+		return span{start: offset, end: len(text), kind: Comment, marker: "/*"}
+	}
+	return span{start: offset, end: offset + 2 + end + 2, kind: Comment, marker: "/*"}
+}
+
+// lineSpan returns the span of a line comment from offset to the end of its line
+func lineSpan(text string, offset int, marker string) span {
+	end := strings.IndexByte(text[offset:], '\n')
+	if end < 0 {
+		end = len(text)
+	} else {
+		end += offset
+	}
+	end = offset + len(strings.TrimRight(text[offset:end], "\r"))
+	return span{start: offset, end: end, kind: Comment, marker: marker}
+}
+
+// stringSpan returns the span of the string literal at offset if it reads as prose: it
+// holds a letter and a space and is not a struct tag. Unterminated strings are skipped.
+func stringSpan(text string, offset int, lit string) (span, bool) {
+	quote := text[offset : offset+1]
+	end := offset + len(lit)
+	if quote == "`" {
+		// go/scanner drops carriage returns from raw strings, so find the closing quote
+		closing := strings.IndexByte(text[offset+1:], '`')
+		if closing < 0 {
+			return span{}, false
+		}
+		end = offset + 1 + closing + 1
+	} else if len(lit) < 2 || !strings.HasSuffix(lit, quote) {
+		// An interpreted string left open at the line end
+		return span{}, false
+	}
+	content := text[offset+1 : end-1]
+	hasLetter := strings.IndexFunc(content, unicode.IsLetter) >= 0
+	if !hasLetter || !strings.ContainsAny(content, " \t") || structTagPattern.MatchString(content) {
+		return span{}, false
+	}
+	return span{start: offset, end: end, kind: String, marker: quote}, true
+}
+
+// nextSignificant returns the index of the first token after i that is not a comment or
+// an automatic semicolon, or -1
+func nextSignificant(tokens []goToken, i int) int {
+	for j := i + 1; j < len(tokens); j++ {
+		if tokens[j].tok == token.COMMENT || (tokens[j].tok == token.SEMICOLON && tokens[j].lit == "\n") {
+			continue
+		}
+		return j
+	}
+	return -1
+}
+
+// documentedName returns the name declared right after the comment at i: the function,
+// method, type, variable or constant, or the struct field or grouped spec. It returns ""
+// when the comment is not followed by a declaration.
+func documentedName(tokens []goToken, i int) string {
+	j := nextSignificant(tokens, i)
+	if j < 0 {
+		return ""
+	}
+	switch tokens[j].tok {
+	case token.IDENT:
+		return tokens[j].lit
+	case token.TYPE, token.VAR, token.CONST:
+		j = nextSignificant(tokens, j)
+	case token.FUNC:
+		j = nextSignificant(tokens, j)
+		if j >= 0 && tokens[j].tok == token.LPAREN {
+			// Skip the receiver of a method
+			for depth := 0; j < len(tokens); j++ {
+				if tokens[j].tok == token.LPAREN {
+					depth++
+				} else if tokens[j].tok == token.RPAREN {
+					if depth--; depth == 0 {
+						break
+					}
+				}
+			}
+			j = nextSignificant(tokens, j)
+		}
+	default:
+		return ""
+	}
+	if j >= 0 && j < len(tokens) && tokens[j].tok == token.IDENT {
+		return tokens[j].lit
+	}
+	return ""
+}
+
+// isCgoPreamble reports whether the comment at i is the C code before import "C"
+func isCgoPreamble(tokens []goToken, i int) bool {
+	j := nextSignificant(tokens, i)
+	return j >= 0 && tokens[j].tok == token.IMPORT && j+1 < len(tokens) && tokens[j+1].lit == `"C"`
+}
+
+// splitSyntax cuts text into prose and the matches of the patterns, which are kept as written
+func splitSyntax(text string, patterns ...*regexp.Regexp) []Inline {
+	keep := make([]bool, len(text))
+	for _, re := range patterns {
+		for _, m := range re.FindAllStringIndex(text, -1) {
+			for k := m[0]; k < m[1]; k++ {
+				keep[k] = true
+			}
+		}
+	}
+
+	var inlines []Inline
+	for i := 0; i < len(text); {
+		j := i
+		for j < len(text) && keep[j] == keep[i] {
+			j++
+		}
+		inlines = append(inlines, Inline{Text: text[i:j], Prose: !keep[i]})
+		i = j
+	}
+	return inlines
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 2025 Spiros Nikoloudakis
+// Licensed under MIT License - see LICENSE file for details
+
+package source
+
+import "strings"
+
+// Comment styles for ParseComments
+const (
+	HashComments  = "#"  // # to the end of the line: shell, Python, Ruby, YAML, TOML
+	SlashComments = "//" // // to the end of the line and /* */: C, Java, JavaScript, Rust
+)
+
+// ParseComments splits the source of a language with hash or slash comments into code and
+// comments. The lexer knows no more of the language than its quoted strings, which it skips
+// so that a # or // inside them is not taken for a comment: "..." and '...' end at the line
+// end, `...`, """...""" and ”'...”' can span lines.
+//
+// A # only starts a comment at the start of a line or after whitespace, so $# and
+// url#anchor stay code, and a #! line at the top of a script is code too. Comment lines
+// indented past a single space are kept as written, like code examples.
+func ParseComments(text, style string) *Document {
+	var spans []span
+	for i := 0; i < len(text); {
+		rest := text[i:]
+		switch {
+		case strings.HasPrefix(rest, `"""`) || strings.HasPrefix(rest, "'''"):
+			i = quotedEnd(text, i+3, rest[:3], false)
+		case rest[0] == '"' || rest[0] == '\'':
+			i = quotedEnd(text, i+1, rest[:1], true)
+		case rest[0] == '`':
+			i = quotedEnd(text, i+1, "`", false)
+		case style == HashComments && rest[0] == '#' && (i == 0 || strings.IndexByte(" \t\n", text[i-1]) >= 0):
+			s := lineSpan(text, i, "#")
+			if i > 0 || !strings.HasPrefix(rest, "#!") {
+				spans = append(spans, s)
+			}
+			i = s.end
+		case style == SlashComments && (strings.HasPrefix(rest, "//") || strings.HasPrefix(rest, "/*")):
+			s := commentSpan(text, i)
+			spans = append(spans, s)
+			i = s.end
+		default:
+			i++
+		}
+	}
+
+	b := &builder{doc: &Document{Text: text}, text: text, preformatted: true}
+	return b.build(spans)
+}
+
+// quotedEnd returns the offset just past the quote that closes a string whose content
+// starts at text[start]. Backslashes escape the next character. A single-line string left
+// open at the line end, such as an apostrophe in a shell comment, ends there.
+func quotedEnd(text string, start int, quote string, singleLine bool) int {
+	for i := start; i < len(text); i++ {
+		switch {
+		case text[i] == '\\':
+			i++
+		case strings.HasPrefix(text[i:], quote):
+			return i + len(quote)
+		case singleLine && text[i] == '\n':
+			return i
+		}
+	}
+	return len(text)
+}
@@ -0,0 +1,328 @@
+// Copyright (c) 2025 Spiros Nikoloudakis
+// Licensed under MIT License - see LICENSE file for details
+
+package source
+
+import (
+	"go-reloaded/internal/layout"
+	"sort"
+	"strings"
+)
+
+// Kind tells what a block of a source file holds
+type Kind int
+
+const (
+	Code    Kind = iota // copied byte-for-byte: code, comment markers, directives and preformatted comment lines
+	Comment             // the text of a comment, or of a run of line comments forming one paragraph
+	String              // the text of a string literal
+)
+
+// Inline is a piece of a block's content: prose to transform, or text to keep as
+// written, such as an escape sequence, a format verb or the name a doc comment documents
+type Inline = layout.Inline
+
+// Block is code or the text of one comment paragraph or string literal. The markers
+// around the text are stored as line prefixes and suffixes, so Content holds only prose.
+type Block struct {
+	Kind    Kind
+	Raw     string   // exact source of the block
+	Content string   // text lines joined by "\n"
+	Inlines []Inline // the pieces of Content
+
+	// Prefixes hold indentation and //, # or /* markers; Suffixes hold trailing spaces
+	// and */; Final is always empty, the line ending after a block belongs to the code
+	layout.Lines
+}
+
+// Document holds the blocks of a source file in order; their Raw texts add up to Text
+type Document struct {
+	Text   string
+	Blocks []*Block
+}
+
+// span is a comment or string literal found by a lexer, as byte offsets into the text
+type span struct {
+	start, end int
+	kind       Kind
+	marker     string // "//", "#" or "/*" for comments, the opening quote for strings
+	name       string // for a doc comment, the documented name to keep as written
+}
+
+// builder turns the spans found by a lexer into blocks
+type builder struct {
+	doc          *Document
+	text         string
+	preformatted bool            // comment lines indented past the usual single space are code examples
+	needsSpace   bool            // line comments without a space after the marker are directives (//go:build)
+	pending      strings.Builder // code not yet added as a block
+}
+
+// build creates the document for a text and its comment and string spans
+func (b *builder) build(spans []span) *Document {
+	sort.Slice(spans, func(i, j int) bool { return spans[i].start < spans[j].start })
+	last := 0
+	for i := 0; i < len(spans); i++ {
+		s := spans[i]
+		b.code(b.text[last:s.start])
+		switch {
+		case s.kind == String:
+			b.stringLiteral(s)
+		case s.marker == "/*":
+			b.blockComment(s)
+		default:
+			// Line comments on consecutive lines with the same indentation form one group
+			group := []span{s}
+			for i+1 < len(spans) && b.continues(group[len(group)-1], spans[i+1]) {
+				i++
+				group = append(group, spans[i])
+			}
+			b.lineComments(group)
+			s = group[len(group)-1]
+		}
+		last = s.end
+	}
+	b.code(b.text[last:])
+	b.endCode()
+	return b.doc
+}
+
+// code adds text that is copied unchanged. Text up to the next comment or string block
+// is collected and becomes one code block.
+func (b *builder) code(text string) {
+	b.pending.WriteString(text)
+}
+
+// endCode adds the code collected so far as a block
+func (b *builder) endCode() {
+	if b.pending.Len() == 0 {
+		return
+	}
+	b.doc.Blocks = append(b.doc.Blocks, &Block{Kind: Code, Raw: b.pending.String()})
+	b.pending.Reset()
+}
+
+// indentOf returns the whitespace before a span on its line, and whether the span is the
+// first thing on the line
+func (b *builder) indentOf(s span) (string, bool) {
+	lineStart := strings.LastIndexByte(b.text[:s.start], '\n') + 1
+	before := b.text[lineStart:s.start]
+	return before, strings.TrimLeft(before, " \t") == ""
+}
+
+// continues reports whether line comment next directly follows line comment prev in
+// the same column, with nothing but indentation before either of them
+func (b *builder) continues(prev, next span) bool {
+	if next.kind != Comment || next.marker != prev.marker {
+		return false
+	}
+	between := b.text[prev.end:next.start]
+	prevIndent, prevAlone := b.indentOf(prev)
+	nextIndent, nextAlone := b.indentOf(next)
+	return prevAlone && nextAlone && prevIndent == nextIndent && strings.Count(between, "\n") == 1
+}
+
+// commentLine is one line of a comment, cut into its verbatim prefix, its text and its suffix
+type commentLine struct {
+	prefix, text, suffix string
+	prose                bool
+}
+
+// lineComments adds a group of line comments. Empty lines, directives and preformatted
+// lines are code and split the group into paragraphs, each a block of its own.
+func (b *builder) lineComments(group []span) {
+	indent, alone := b.indentOf(group[0])
+	if !alone {
+		indent = strings.Repeat(" ", len(indent))
+	}
+
+	var lines []commentLine
+	for i, s := range group {
+		raw := b.text[s.start:s.end]
+		after := raw[len(s.marker):]
+		body := strings.TrimLeft(after, " \t")
+		spacing := after[:len(after)-len(body)]
+		text := strings.TrimRight(body, " \t\r")
+
+		line := commentLine{prefix: s.marker + spacing, text: text, suffix: body[len(text):]}
+		if i > 0 {
+			line.prefix = indent + line.prefix
+		}
+		line.prose = text != "" &&
+			!(b.needsSpace && (spacing == "" || strings.HasPrefix(text, "+build"))) &&
+			!(b.preformatted && spacing != " " && spacing != "")
+		lines = append(lines, line)
+	}
+
+	newline := "\n"
+	if strings.HasPrefix(b.text[group[0].end:], "\r\n") {
+		newline = "\r\n"
+	}
+	continuation := indent + group[0].marker + " "
+
+	// Between two lines of a group there is always a line ending and the indentation
+	for start := 0; start < len(lines); {
+		if start > 0 {
+			b.code(newline)
+		}
+		if !lines[start].prose {
+			b.code(lines[start].prefix + lines[start].text + lines[start].suffix)
+			start++
+			continue
+		}
+		end := start
+		for end+1 < len(lines) && lines[end+1].prose {
+			end++
+		}
+		name := ""
+		if start == 0 {
+			name = group[0].name
+		}
+		b.prose(Comment, lines[start:end+1], continuation, newline, name)
+		start = end + 1
+	}
+}
+
+// blockComment adds a /* */ comment. Leading and trailing empty lines stay with the
+// markers, and a leading * on the lines in between is kept as part of the prefix.
+func (b *builder) blockComment(s span) {
+	raw := b.text[s.start:s.end]
+	if !strings.HasSuffix(raw, "*/") || len(raw) < 4 {
+		b.code(raw)
+		return
+	}
+	newline := "\n"
+	if strings.Contains(raw, "\r\n") {
+		newline = "\r\n"
+	}
+	inner := strings.Split(raw[2:len(raw)-2], newline)
+
+	lines := make([]commentLine, len(inner))
+	starred := len(inner) > 1
+	for i, line := range inner[1:] {
+		trimmed := strings.TrimLeft(line, " \t")
+		if trimmed != "" && !strings.HasPrefix(trimmed, "*") && i < len(inner)-2 {
+			starred = false
+		}
+	}
+	for i, line := range inner {
+		body := strings.TrimLeft(line, " \t")
+		if i > 0 && starred && strings.HasPrefix(body, "*") {
+			body = strings.TrimLeft(body[1:], " \t")
+		}
+		text := strings.TrimRight(body, " \t\r")
+		lines[i] = commentLine{prefix: line[:len(line)-len(body)], text: text, suffix: body[len(text):], prose: text != ""}
+	}
+	lines[0].prefix = "/*" + lines[0].prefix
+	lines[len(lines)-1].suffix += "*/"
+
+	// Fold empty lines at either end into the markers
+	for len(lines) > 1 && !lines[0].prose {
+		lines[1].prefix = lines[0].prefix + lines[0].text + lines[0].suffix + newline + lines[1].prefix
+		lines = lines[1:]
+	}
+	for n := len(lines); n > 1 && !lines[n-1].prose; n = len(lines) {
+		lines[n-2].suffix += newline + lines[n-1].prefix + lines[n-1].text + lines[n-1].suffix
+		lines = lines[:n-1]
+	}
+	if !lines[0].prose {
+		b.code(raw)
+		return
+	}
+
+	continuation := ""
+	if len(lines) > 1 {
+		continuation = lines[1].prefix
+	}
+	for i := range lines {
+		lines[i].prose = true
+	}
+	b.prose(Comment, lines, continuation, newline, s.name)
+}
+
+// stringLiteral adds a string literal whose content is prose. Escape sequences and
+// format verbs are kept as written.
+func (b *builder) stringLiteral(s span) {
+	raw := b.text[s.start:s.end]
+	inner := raw[len(s.marker) : len(raw)-len(s.marker)]
+	content := strings.TrimSpace(inner)
+	if content == "" {
+		b.code(raw)
+		return
+	}
+	// Spaces around the text are part of the value, so they stay with the quotes
+	leading := inner[:strings.Index(inner, content)]
+	block := &Block{
+		Kind:    String,
+		Raw:     raw,
+		Content: content,
+		Lines: layout.Lines{
+			Prefixes: []string{s.marker + leading},
+			Suffixes: []string{inner[len(leading)+len(content):] + s.marker},
+			Newline:  "\n",
+		},
+	}
+	if s.marker == `"` {
+		block.Inlines = splitSyntax(content, escapePattern, formatVerbPattern)
+	} else {
+		block.Inlines = splitSyntax(content, formatVerbPattern)
+	}
+	b.endCode()
+	b.doc.Blocks = append(b.doc.Blocks, block)
+}
+
+// prose adds a comment block made of the given lines. A leading name, such as the
+// function a doc comment documents, is kept as written.
+func (b *builder) prose(kind Kind, lines []commentLine, continuation, newline, name string) {
+	block := &Block{Kind: kind, Lines: layout.Lines{Continuation: continuation, Newline: newline}}
+	texts := make([]string, len(lines))
+	for i, line := range lines {
+		block.Prefixes = append(block.Prefixes, line.prefix)
+		block.Suffixes = append(block.Suffixes, line.suffix)
+		texts[i] = line.text
+	}
+	block.Content = strings.Join(texts, "\n")
+	block.Raw = block.Assemble(block.Content)
+
+	rest := strings.TrimPrefix(block.Content, name)
+	if name != "" && len(rest) < len(block.Content) && (rest == "" || rest[0] == ' ') {
+		block.Inlines = []Inline{{Text: name}, {Text: rest, Prose: true}}
+	} else {
+		block.Inlines = []Inline{{Text: block.Content, Prose: true}}
+	}
+	b.endCode()
+	b.doc.Blocks = append(b.doc.Blocks, block)
+}
+
+// Rewrite rebuilds the document, replacing the content of every comment and string block
+// with what fn returns for it. Code and the comment markers are copied unchanged.
+func (d *Document) Rewrite(fn func(b *Block) string) string {
+	var result strings.Builder
+	for _, b := range d.Blocks {
+		if b.Kind == Code {
+			result.WriteString(b.Raw)
+			continue
+		}
+		content := fn(b)
+		if content == b.Content {
+			result.WriteString(b.Raw)
+			continue
+		}
+		result.WriteString(b.Assemble(content))
+	}
+	return result.String()
+}
+
+// Mask returns the text with everything but prose blanked out, byte for byte, so checks
+// meant for prose can run on it and report the original positions
+func (d *Document) Mask() string {
+	var result strings.Builder
+	for _, b := range d.Blocks {
+		if b.Kind == Code {
+			result.WriteString(layout.Blank(b.Raw))
+			continue
+		}
+		result.WriteString(b.Lines.Mask(b.Inlines))
+	}
+	return result.String()
+}
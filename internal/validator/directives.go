@@ -0,0 +1,296 @@
+// Copyright (c) 2025 Spiros Nikoloudakis
+// Licensed under MIT License - see LICENSE file for details
+
+package validator
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// KnownRules lists the rule names that directives can disable
+var KnownRules = []string{"numbers", "case", "articles", "typos", "capitalization", "quotes", "punctuation", "typography", "wrap"}
+
+// directivePattern matches (go-reloaded:kind) and (go-reloaded:kind rule,rule) tags
+var directivePattern = regexp.MustCompile(`(?i)\(go-reloaded:([a-z-]*)((?:\s+[a-z]+(?:\s*,\s*[a-z]+)*)?)\s*\)`)
+
+// Directive is a (go-reloaded:...) tag found in the input
+type Directive struct {
+	Kind  string   // off, on, disable, enable or disable-next-line
+	Rules []string // rules named by the directive; empty means every rule
+	Start int      // byte offset of the tag, or of its line when the tag stands alone
+	End   int      // byte offset just after the tag, or after its line when the tag stands alone
+}
+
+// Region is a stretch of input that shares the same directive state.
+// Directive tags themselves are not part of any region.
+type Region struct {
+	Start    int
+	End      int
+	Verbatim bool     // every rule is off and the text is kept byte-for-byte
+	Disabled []string // rules switched off for this region
+}
+
+// FindDirectives returns every unescaped directive in the input, in order
+func FindDirectives(input string) []Directive {
+	var directives []Directive
+	for _, m := range directivePattern.FindAllStringSubmatchIndex(input, -1) {
+		if isEscaped(input, m[0]) {
+			continue
+		}
+		d := Directive{
+			Kind:  strings.ToLower(input[m[2]:m[3]]),
+			Start: m[0],
+			End:   m[1],
+		}
+		// A directive on a line of its own takes the whole line with it
+		lineStart := strings.LastIndexByte(input[:m[0]], '\n') + 1
+		lineEnd := len(input)
+		if next := strings.IndexByte(input[m[1]:], '\n'); next >= 0 {
+			lineEnd = m[1] + next + 1
+		}
+		if strings.TrimSpace(input[lineStart:m[0]]) == "" && strings.TrimSpace(input[m[1]:lineEnd]) == "" {
+			d.Start, d.End = lineStart, lineEnd
+		}
+		for _, rule := range strings.Split(input[m[4]:m[5]], ",") {
+			if rule = strings.ToLower(strings.TrimSpace(rule)); rule != "" {
+				d.Rules = append(d.Rules, rule)
+			}
+		}
+		directives = append(directives, d)
+	}
+	return directives
+}
+
+// SplitRegions divides the input into regions according to its directives.
+// (go-reloaded:off) ... (go-reloaded:on) regions are verbatim, (go-reloaded:disable rules)
+// lasts until a matching enable, and (go-reloaded:disable-next-line rules) covers the line
+// after the directive.
+func SplitRegions(input string) []Region {
+	return splitRegions(input, FindDirectives(input))
+}
+
+// splitRegions divides the input into regions using directives already found in it
+func splitRegions(input string, directives []Directive) []Region {
+	if len(directives) == 0 {
+		return []Region{{Start: 0, End: len(input)}}
+	}
+
+	// Every directive edge and next-line range is a potential region boundary
+	boundaries := []int{0, len(input)}
+	type lineRange struct {
+		start, end int
+		rules      []string
+	}
+	var nextLines []lineRange
+	for _, d := range directives {
+		boundaries = append(boundaries, d.Start, d.End)
+		if d.Kind == "disable-next-line" {
+			start := d.End
+			if d.End == 0 || input[d.End-1] != '\n' {
+				newline := strings.IndexByte(input[d.End:], '\n')
+				if newline < 0 {
+					continue
+				}
+				start = d.End + newline + 1
+			}
+			end := len(input)
+			if next := strings.IndexByte(input[start:], '\n'); next >= 0 {
+				end = start + next
+			}
+			nextLines = append(nextLines, lineRange{start: start, end: end, rules: d.Rules})
+			boundaries = append(boundaries, start, end)
+		}
+	}
+	sort.Ints(boundaries)
+
+	// Directives and next-line ranges are both in input order, so the state is
+	// carried forward as the boundaries are walked instead of replayed per region
+	var regions []Region
+	off := false
+	disabled := map[string]bool{}
+	applied, line := 0, 0
+	for i := 0; i+1 < len(boundaries); i++ {
+		start, end := boundaries[i], boundaries[i+1]
+		for ; applied < len(directives) && directives[applied].Start <= start; applied++ {
+			switch d := directives[applied]; d.Kind {
+			case "off":
+				off = true
+			case "on":
+				off = false
+			case "disable":
+				setRules(disabled, d.Rules, true)
+			case "enable":
+				setRules(disabled, d.Rules, false)
+			}
+		}
+		// Directive tags never overlap, so only the latest one can contain start
+		if start == end || (applied > 0 && start < directives[applied-1].End) {
+			continue
+		}
+
+		// Next-line ranges are whole lines, so they are either equal or apart
+		for line < len(nextLines) && nextLines[line].end < end {
+			line++
+		}
+		state := disabled
+		if line < len(nextLines) && nextLines[line].start <= start {
+			state = map[string]bool{}
+			for rule := range disabled {
+				state[rule] = true
+			}
+			for j := line; j < len(nextLines) && nextLines[j].start <= start; j++ {
+				setRules(state, nextLines[j].rules, true)
+			}
+		}
+
+		region := Region{Start: start, End: end, Verbatim: off || len(state) == len(KnownRules)}
+		if !region.Verbatim {
+			for _, rule := range KnownRules {
+				if state[rule] {
+					region.Disabled = append(region.Disabled, rule)
+				}
+			}
+		}
+
+		// Merge with the previous region when nothing changes between them
+		if n := len(regions); n > 0 && regions[n-1].End == start && sameState(regions[n-1], region) {
+			regions[n-1].End = end
+			continue
+		}
+		regions = append(regions, region)
+	}
+	return regions
+}
+
+// setRules marks rules as disabled or enabled; no rules means every rule
+func setRules(disabled map[string]bool, rules []string, value bool) {
+	if len(rules) == 0 {
+		rules = KnownRules
+	}
+	for _, rule := range rules {
+		if value {
+			disabled[rule] = true
+		} else {
+			delete(disabled, rule)
+		}
+	}
+}
+
+// sameState reports whether two regions are processed the same way
+func sameState(a, b Region) bool {
+	return a.Verbatim == b.Verbatim && strings.Join(a.Disabled, ",") == strings.Join(b.Disabled, ",")
+}
+
+// validateDirectives checks directive names, rule names and off/on pairing
+func validateDirectives(input string) error {
+	off := false
+	for _, d := range FindDirectives(input) {
+		switch d.Kind {
+		case "off":
+			off = true
+		case "on":
+			if !off {
+				return ValidationError{
+					Type:     "INVALID_DIRECTIVE",
+					Position: d.Start,
+					Message:  "You have (go-reloaded:on) without a matching (go-reloaded:off). Please add the off directive or remove the on directive.",
+				}
+			}
+			off = false
+		case "disable", "enable", "disable-next-line":
+		default:
+			return ValidationError{
+				Type:     "INVALID_DIRECTIVE",
+				Position: d.Start,
+				Message:  fmt.Sprintf("Unknown directive (go-reloaded:%s). Use off, on, disable, enable or disable-next-line.", d.Kind),
+			}
+		}
+
+		for _, rule := range d.Rules {
+			if !isKnownRule(rule) {
+				return ValidationError{
+					Type:     "INVALID_DIRECTIVE",
+					Position: d.Start,
+					Message:  fmt.Sprintf("Unknown rule '%s' in directive. Known rules are: %s.", rule, strings.Join(KnownRules, ", ")),
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// isKnownRule checks a rule name against KnownRules
+func isKnownRule(rule string) bool {
+	for _, known := range KnownRules {
+		if rule == known {
+			return true
+		}
+	}
+	return false
+}
+
+// MaskVerbatim blanks out directive tags and verbatim regions so syntax checks skip them.
+// Each character becomes one space and newlines are kept, so the rune positions that
+// validation errors report stay unchanged.
+func MaskVerbatim(input string) string {
+	return maskVerbatim(input, func(masked *strings.Builder, text string) {
+		for _, r := range text {
+			if r == '\n' {
+				masked.WriteRune(r)
+			} else {
+				masked.WriteByte(' ')
+			}
+		}
+	})
+}
+
+// MaskVerbatimBytes is MaskVerbatim for callers that work with byte offsets into the
+// input: every byte but a newline becomes a space, so the length stays the same.
+func MaskVerbatimBytes(input string) string {
+	return maskVerbatim(input, func(masked *strings.Builder, text string) {
+		for i := 0; i < len(text); i++ {
+			if text[i] == '\n' {
+				masked.WriteByte('\n')
+			} else {
+				masked.WriteByte(' ')
+			}
+		}
+	})
+}
+
+// maskVerbatim copies the input, writing directive tags and verbatim regions with blank
+func maskVerbatim(input string, blank func(masked *strings.Builder, text string)) string {
+	directives := FindDirectives(input)
+	if len(directives) == 0 {
+		return input
+	}
+
+	// Directives inside a verbatim region are blanked with it, so the spans never overlap
+	var spans [][2]int
+	for _, region := range splitRegions(input, directives) {
+		if region.Verbatim {
+			spans = append(spans, [2]int{region.Start, region.End})
+		}
+	}
+	for _, d := range directives {
+		spans = append(spans, [2]int{d.Start, d.End})
+	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i][0] < spans[j][0] })
+
+	var masked strings.Builder
+	last := 0
+	for _, span := range spans {
+		start, end := max(span[0], last), span[1]
+		if start >= end {
+			continue
+		}
+		masked.WriteString(input[last:start])
+		blank(&masked, input[start:end])
+		last = end
+	}
+	masked.WriteString(input[last:])
+	return masked.String()
+}
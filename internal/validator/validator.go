@@ -5,7 +5,10 @@ package validator
 
 import (
 	"fmt"
+	"go-reloaded/internal/pairing"
+	"regexp"
 	"strings"
+	"unicode"
 )
 
 const (
@@ -13,8 +16,25 @@ const (
 	MaxLineLength = 100000          // 100K chars per line
 	MaxNestingDepth = 50            // Max nested parentheses
 	MaxTransformations = 1000       // Max transformations per input
+	MaxExpressionLength = 256       // Max characters in a (calc) expression
 )
 
+// modifierPattern matches modifier tags in any letter case, including chains like (low|cap, 3)
+// and block markers like (up:start)
+var modifierPattern = regexp.MustCompile(`\(([A-Za-z][A-Za-z0-9_:|\s]*)(?:,[^()]*)?\)`)
+
+// modifierNames lists every modifier counted towards MaxTransformations
+var modifierNames = map[string]bool{
+	"hex": true, "bin": true, "roman": true, "toroman": true, "calc": true,
+	"sep": true, "round": true, "ord": true, "pct": true, "words": true, "num": true,
+	"up": true, "low": true, "cap": true, "title": true, "sentence": true,
+	"snake": true, "camel": true, "kebab": true, "pascal": true, "const": true,
+	"dq": true, "sq": true, "wrap": true,
+}
+
+// blockMarkerPattern matches case block markers such as (up:start) and (up:end)
+var blockMarkerPattern = regexp.MustCompile(`(?i)\(([a-z]+):(start|end)\)`)
+
 type ValidationError struct {
 	Type     string
 	Position int
@@ -29,15 +49,24 @@ func (e ValidationError) Error() string {
 	return e.Message
 }
 
-// ValidateInput performs comprehensive input validation
-func ValidateInput(input string) error {
-	// Check buffer overflow protection
+// ValidateSize checks the input against MaxInputSize. It is cheap, so callers run it
+// before any other work on the input, such as parsing a structured document.
+func ValidateSize(input string) error {
 	if len(input) > MaxInputSize {
 		return ValidationError{
 			Type:    "BUFFER_OVERFLOW",
 			Message: fmt.Sprintf("Input size %d exceeds maximum allowed %d bytes", len(input), MaxInputSize),
 		}
 	}
+	return nil
+}
+
+// ValidateInput performs comprehensive input validation
+func ValidateInput(input string) error {
+	// Check buffer overflow protection
+	if err := ValidateSize(input); err != nil {
+		return err
+	}
 
 	// Check line length limits
 	lines := strings.Split(input, "\n")
@@ -52,13 +81,26 @@ func ValidateInput(input string) error {
 		}
 	}
 
+	// Check (go-reloaded:...) directives
+	if err := validateDirectives(input); err != nil {
+		return err
+	}
+
+	// Verbatim regions are kept as written, so syntax checks skip them
+	checked := MaskVerbatim(input)
+
 	// Check for unclosed brackets and quotes
-	if err := validateBrackets(input); err != nil {
+	if err := validateBrackets(checked); err != nil {
+		return err
+	}
+
+	// Check that every (name:start) block has a matching (name:end)
+	if err := validateCaseBlocks(checked); err != nil {
 		return err
 	}
 
 	// Check for excessive transformations (DoS protection)
-	if err := validateTransformationCount(input); err != nil {
+	if err := validateTransformationCount(checked); err != nil {
 		return err
 	}
 
@@ -77,83 +119,56 @@ func ValidateInput(input string) error {
 
 // validateBrackets checks for unclosed parentheses and quotes
 func validateBrackets(input string) error {
-	var parenStack []int
-	var singleQuotes []int
-	var doubleQuotes []int
-	
-	runes := []rune(input)
-	
-	for i, r := range runes {
-		switch r {
-		case '(':
-			parenStack = append(parenStack, i)
-		case ')':
-			if len(parenStack) == 0 {
-				return ValidationError{
-					Type:     "UNMATCHED_BRACKET",
-					Position: i,
-					Message:  "You have a closing parenthesis ()) without a matching opening parenthesis. Please add the opening parenthesis or remove the extra closing one.",
-					
-				}
-			}
-			parenStack = parenStack[:len(parenStack)-1]
-		case '\'':
-			// Skip contractions (apostrophes between letters)
-			if isContraction(runes, i) {
-				continue
-			}
-			if len(singleQuotes) > 0 && singleQuotes[len(singleQuotes)-1] != -1 {
-				singleQuotes[len(singleQuotes)-1] = -1 // Mark as closed
-			} else {
-				singleQuotes = append(singleQuotes, i)
-			}
-		case '"':
-			if len(doubleQuotes) > 0 && doubleQuotes[len(doubleQuotes)-1] != -1 {
-				doubleQuotes[len(doubleQuotes)-1] = -1 // Mark as closed
-			} else {
-				doubleQuotes = append(doubleQuotes, i)
+	tree := pairing.Parse(input)
+
+	for _, mark := range tree.Find(pairing.Unmatched) {
+		if mark.Char == ')' {
+			return ValidationError{
+				Type:     "UNMATCHED_BRACKET",
+				Position: mark.Index,
+				Message:  "You have a closing parenthesis ()) without a matching opening parenthesis. Please add the opening parenthesis or remove the extra closing one.",
 			}
 		}
 	}
 
 	// Check nesting depth
-	if len(parenStack) > MaxNestingDepth {
+	if depth := tree.MaxDepth(); depth > MaxNestingDepth {
 		return ValidationError{
 			Type:    "EXCESSIVE_NESTING",
-			Message: fmt.Sprintf("Parentheses nesting depth %d exceeds maximum %d", len(parenStack), MaxNestingDepth),
+			Message: fmt.Sprintf("Nesting depth %d of quotes and brackets exceeds maximum %d", depth, MaxNestingDepth),
 		}
 	}
 
+	// Openings left without a partner; other brackets are often used on their own, as in "a < b"
+	unclosed := map[rune][]int{}
+	for _, mark := range tree.Find(pairing.Unclosed) {
+		unclosed[mark.Char] = append(unclosed[mark.Char], mark.Index)
+	}
+
 	// Check for unclosed parentheses
-	if len(parenStack) > 0 {
-		pos := parenStack[0]
+	if positions := unclosed['(']; len(positions) > 0 {
 		return ValidationError{
 			Type:     "UNCLOSED_BRACKET",
-			Position: pos,
+			Position: positions[0],
 			Message:  "You have an unclosed opening parenthesis (() in your text. Please add the closing parenthesis or remove it if not needed.",
-			
 		}
 	}
 
 	// Check for unclosed single quotes
-	for _, pos := range singleQuotes {
-		if pos != -1 {
-			return ValidationError{
-				Type:     "UNCLOSED_QUOTE",
-				Position: pos,
-				Message:  "You have an unclosed single quote (') in your text. Please add the closing quote or remove it if not needed.",
-			}
+	if positions := unclosed['\'']; len(positions) > 0 {
+		return ValidationError{
+			Type:     "UNCLOSED_QUOTE",
+			Position: positions[0],
+			Message:  "You have an unclosed single quote (') in your text. Please add the closing quote or remove it if not needed.",
 		}
 	}
 
 	// Check for unclosed double quotes
-	for _, pos := range doubleQuotes {
-		if pos != -1 {
-			return ValidationError{
-				Type:     "UNCLOSED_QUOTE",
-				Position: pos,
-				Message:  "You have an unclosed double quote (\") in your text. Please add the closing quote or remove it if not needed.",
-			}
+	if positions := unclosed['"']; len(positions) > 0 {
+		return ValidationError{
+			Type:     "UNCLOSED_QUOTE",
+			Position: positions[0],
+			Message:  "You have an unclosed double quote (\") in your text. Please add the closing quote or remove it if not needed.",
 		}
 	}
 
@@ -165,12 +180,15 @@ func getContext(runes []rune, pos int) string {
 	start := max(0, pos-20)
 	end := min(len(runes), pos+21)
 	
-	context := string(runes[start:end])
+	if start > end {
+		return ""
+	}
+	contextRunes := runes[start:end]
+	context := string(contextRunes)
 	
 	// Highlight the problem character
 	relativePos := pos - start
-	if relativePos >= 0 && relativePos < len(context) {
-		contextRunes := []rune(context)
+	if relativePos >= 0 && relativePos < len(contextRunes) {
 		highlighted := string(contextRunes[:relativePos]) + ">>>" + string(contextRunes[relativePos]) + "<<<" + string(contextRunes[relativePos+1:])
 		return highlighted
 	}
@@ -216,20 +234,6 @@ func max(a, b int) int {
 	return b
 }
 
-// isContraction checks if apostrophe is part of a contraction like "don't"
-func isContraction(runes []rune, pos int) bool {
-	if pos <= 0 || pos >= len(runes)-1 {
-		return false
-	}
-	// Check if surrounded by letters
-	return isLetter(runes[pos-1]) && isLetter(runes[pos+1])
-}
-
-// isLetter checks if rune is a letter
-func isLetter(r rune) bool {
-	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
-}
-
 // isKeyboardCharacter checks if rune is a standard keyboard character
 func isKeyboardCharacter(r rune) bool {
 	// Allow basic whitespace
@@ -247,10 +251,17 @@ func isKeyboardCharacter(r rune) bool {
 		return true
 	}
 	
-	// Allow common Unicode characters that appear on keyboards
+	// Allow Greek keyboards, so text for the "el" punctuation profile can be checked
+	if unicode.Is(unicode.Greek, r) {
+		return true
+	}
+	
+	// Allow common Unicode characters that appear on keyboards, including the
+	// narrow no-break space the "fr" punctuation profile writes
 	commonUnicode := []rune{
 		'€', '£', '¥', '©', '®', '™', '°', '±', '²', '³',
-		'¼', '½', '¾', '×', '÷', 'α', 'β', 'γ', 'δ', 'π',
+		'¼', '½', '¾', '×', '÷',
+		'‘', '’', '“', '”', '„', '–', '—', '…', '\u202f',
 	}
 	
 	for _, char := range commonUnicode {
@@ -262,10 +273,70 @@ func isKeyboardCharacter(r rune) bool {
 	return false
 }
 
+// validateCaseBlocks checks that case block markers are balanced and properly nested
+func validateCaseBlocks(input string) error {
+	type marker struct {
+		name     string
+		position int
+	}
+	var stack []marker
+
+	for _, m := range blockMarkerPattern.FindAllStringSubmatchIndex(input, -1) {
+		if isEscaped(input, m[0]) || isDoubled(input, m[0], m[1]) {
+			continue
+		}
+		name := strings.ToLower(input[m[2]:m[3]])
+		if strings.EqualFold(input[m[4]:m[5]], "start") {
+			stack = append(stack, marker{name: name, position: m[0]})
+			continue
+		}
+		if len(stack) == 0 || stack[len(stack)-1].name != name {
+			return ValidationError{
+				Type:     "UNBALANCED_BLOCK",
+				Position: m[0],
+				Message:  fmt.Sprintf("The block end (%s:end) has no matching (%s:start). Please add the start marker or remove the end marker.", name, name),
+			}
+		}
+		stack = stack[:len(stack)-1]
+	}
+
+	if len(stack) > 0 {
+		open := stack[len(stack)-1]
+		return ValidationError{
+			Type:     "UNBALANCED_BLOCK",
+			Position: open.position,
+			Message:  fmt.Sprintf("The block (%s:start) is never closed. Please add (%s:end) where the block should stop.", open.name, open.name),
+		}
+	}
+	return nil
+}
+
 // validateTransformationCount prevents DoS attacks via excessive transformations
 func validateTransformationCount(input string) error {
-	count := strings.Count(input, "(hex)") + strings.Count(input, "(bin)") +
-		strings.Count(input, "(up)") + strings.Count(input, "(low)") + strings.Count(input, "(cap)")
+	return ValidateTransformations(input, func(name string) []string { return []string{name} })
+}
+
+// ValidateTransformations checks the number of modifiers in the input against
+// MaxTransformations. Every name of a chain counts, and resolve expands each lower-case
+// name first, so a user-defined alias for "low|cap" counts as two.
+func ValidateTransformations(input string, resolve func(name string) []string) error {
+	count := 0
+	for _, m := range modifierPattern.FindAllStringSubmatchIndex(input, -1) {
+		if isEscaped(input, m[0]) || isDoubled(input, m[0], m[1]) {
+			continue
+		}
+		for _, name := range strings.Split(input[m[2]:m[3]], "|") {
+			name = strings.ToLower(strings.TrimSpace(name))
+			if colon := strings.Index(name, ":"); colon >= 0 {
+				name = name[:colon]
+			}
+			for _, resolved := range resolve(name) {
+				if modifierNames[resolved] {
+					count++
+				}
+			}
+		}
+	}
 	
 	if count > MaxTransformations {
 		return ValidationError{
@@ -276,6 +347,17 @@ func validateTransformationCount(input string) error {
 	return nil
 }
 
+// isEscaped reports whether the modifier starting at pos is written as literal text, e.g. \(up)
+func isEscaped(input string, pos int) bool {
+	return pos > 0 && input[pos-1] == '\\'
+}
+
+// isDoubled reports whether the modifier input[start:end] is written in doubled
+// parentheses, e.g. ((up)), which also makes it literal text
+func isDoubled(input string, start, end int) bool {
+	return start > 0 && input[start-1] == '(' && end < len(input) && input[end] == ')'
+}
+
 // validateMaliciousPatterns checks for potentially malicious input patterns
 func validateMaliciousPatterns(input string) error {
 	// Check for null bytes
@@ -301,14 +383,16 @@ func validateMaliciousPatterns(input string) error {
 
 // validateTextContent ensures input contains only valid keyboard characters
 func validateTextContent(input string) error {
-	// Check for keyboard character compliance
-	for i, r := range input {
+	// Check for keyboard character compliance; positions are rune indexes so
+	// they line up with getContext
+	runes := []rune(input)
+	for i, r := range runes {
 		if !isKeyboardCharacter(r) {
 			return ValidationError{
 				Type:     "NON_KEYBOARD_CHARACTER",
 				Position: i,
 				Message:  fmt.Sprintf("Non-keyboard character detected: '%c' (U+%04X). Please use only standard keyboard characters.", r, r),
-				Context:  getContext([]rune(input), i),
+				Context:  getContext(runes, i),
 			}
 		}
 	}
@@ -0,0 +1,591 @@
+// Copyright (c) 2025 Spiros Nikoloudakis
+// Licensed under MIT License - see LICENSE file for details
+
+package tests
+
+import (
+	"fmt"
+	"go-reloaded/internal/processor"
+	"strings"
+	"testing"
+)
+
+func TestExtendedCaseModifiers(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "Title case keeps small words",
+			input:    "read the lord of the rings (title, 5) tonight",
+			expected: "read The Lord of the Rings tonight",
+		},
+		{
+			name:     "Title case capitalizes first and last small words",
+			input:    "what is it for (title, 4) anyway",
+			expected: "What Is It For anyway",
+		},
+		{
+			name:     "Sentence case",
+			input:    "THIS IS LOUD (sentence, 3) now",
+			expected: "This is loud now",
+		},
+		{
+			name:     "Snake case merges words",
+			input:    "call user account id (snake, 3) first",
+			expected: "call user_account_id first",
+		},
+		{
+			name:     "Camel case",
+			input:    "set max retry count (camel, 3) to five",
+			expected: "set maxRetryCount to five",
+		},
+		{
+			name:     "Kebab case keeps trailing punctuation",
+			input:    "open main menu (kebab, 2).",
+			expected: "open main-menu.",
+		},
+		{
+			name:     "Pascal and const case",
+			input:    "type user profile (pascal, 2) and max size (const, 2) values",
+			expected: "type UserProfile and MAX_SIZE values",
+		},
+		{
+			name:     "Quoted span",
+			input:    "the ' http request handler (pascal) ' type",
+			expected: "the 'HttpRequestHandler' type",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := processor.ProcessText(tt.input)
+			if result != tt.expected {
+				t.Errorf("\nInput:    %q\nExpected: %q\nGot:      %q", tt.input, tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestTitleSmallWordExceptions(t *testing.T) {
+	opts := processor.DefaultOptions()
+	opts.TitleSmallWords = []string{"is"}
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "Configured small word",
+			input:    "this is sparta (title, 3) indeed",
+			expected: "This is Sparta indeed",
+		},
+	}
+
+	processor.SetOptions(opts)
+	defer processor.SetOptions(processor.DefaultOptions())
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := processor.ProcessText(tt.input)
+			if result != tt.expected {
+				t.Errorf("\nInput:    %q\nExpected: %q\nGot:      %q", tt.input, tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestScopedAndBlockModifiers(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "Forward count",
+			input:    "please (up, +3) read this manual carefully",
+			expected: "please READ THIS MANUAL carefully",
+		},
+		{
+			name:     "Forward single word",
+			input:    "the (cap, +1) river flows",
+			expected: "the River flows",
+		},
+		{
+			name:     "Forward count skips later modifiers",
+			input:    "(cap, +3) one two (low, 2) three",
+			expected: "one two Three",
+		},
+		{
+			name:     "Merged identifier with punctuation keeps its words apart",
+			input:    "and -(snake, 2) (up)",
+			expected: "and -",
+		},
+		{
+			name:     "Sentence scope",
+			input:    "First part. all of this sentence (cap, sentence) counts.",
+			expected: "First part. All Of This Sentence counts.",
+		},
+		{
+			name:     "Line scope",
+			input:    "Keep This\nMAKE THIS QUIET (low, line) now",
+			expected: "Keep This make this quiet now",
+		},
+		{
+			name:     "Block markers",
+			input:    "say (up:start) hello there friend (up:end) softly",
+			expected: "say HELLO THERE FRIEND softly",
+		},
+		{
+			name:     "Nested block and modifier",
+			input:    "(up:start) one (low:start) two three (low:end) four FIVE (low) (up:end)",
+			expected: "ONE two three FOUR five",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := processor.ProcessText(tt.input)
+			if result != tt.expected {
+				t.Errorf("\nInput:    %q\nExpected: %q\nGot:      %q", tt.input, tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestComposableModifiers(t *testing.T) {
+	opts := processor.DefaultOptions()
+	opts.Aliases = map[string]string{"u": "up", "lc": "low|cap", "Q": "up", "q": "low"}
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "Upper-case modifier name",
+			input:    "make this loud (UP) now",
+			expected: "make this LOUD now",
+		},
+		{
+			name:     "Mixed-case modifier with count",
+			input:    "these two words (Cap, 2) here",
+			expected: "these Two Words here",
+		},
+		{
+			name:     "Chained case modifiers",
+			input:    "MIXED case TEXT (low|cap, 3) done",
+			expected: "Mixed Case Text done",
+		},
+		{
+			name:     "Chained number modifiers",
+			input:    "Size FFFFF (hex|sep) bytes.",
+			expected: "Size 1,048,575 bytes.",
+		},
+		{
+			name:     "Chain links see the previous link's output",
+			input:    "x 12 (words|up) and twelve (up|num) y",
+			expected: "x TWELVE and 12 y",
+		},
+		{
+			name:     "Chain across stages in written order",
+			input:    "Lucky XIII (roman|words|cap) day",
+			expected: "Lucky Thirteen day",
+		},
+		{
+			name:     "Stacked modifiers apply in written order",
+			input:    "SHOUT (low) (cap) quietly",
+			expected: "Shout quietly",
+		},
+		{
+			name:     "Alias",
+			input:    "short word (u) here",
+			expected: "short WORD here",
+		},
+		{
+			name:     "Alias for a chain",
+			input:    "BIG NEWS (lc, 2) today",
+			expected: "Big News today",
+		},
+		{
+			name:     "Alias written exactly as the tag wins",
+			input:    "QUIET word (Q) please",
+			expected: "QUIET word please",
+		},
+		{
+			name:     "Chain whose first link fails stays as written",
+			input:    "Code GH (hex|sep) here",
+			expected: "Code GH (hex|sep) here",
+		},
+		{
+			name:     "Unknown names stay as text",
+			input:    "see the appendix (Table) below",
+			expected: "see the appendix (Table) below",
+		},
+	}
+
+	processor.SetOptions(opts)
+	defer processor.SetOptions(processor.DefaultOptions())
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := processor.ProcessText(tt.input)
+			if result != tt.expected {
+				t.Errorf("\nInput:    %q\nExpected: %q\nGot:      %q", tt.input, tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestAliasesCountAsTransformations(t *testing.T) {
+	opts := processor.DefaultOptions()
+	opts.Aliases = map[string]string{"lc": "low|cap"}
+	processor.SetOptions(opts)
+	defer processor.SetOptions(processor.DefaultOptions())
+
+	input := strings.Repeat("word (lc) ", 600)
+	if err := processor.ValidateText(input); err == nil || !strings.Contains(err.Error(), "1200 transformations") {
+		t.Errorf("Expected 1200 transformations to be rejected, got %v", err)
+	}
+	if err := processor.ValidateText(strings.Repeat("word (low|cap) ", 600)); err == nil {
+		t.Errorf("Expected a chain of two to count twice")
+	}
+}
+
+func TestEscapedModifiers(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "Escaped case modifier",
+			input:    "Write word \\(up) to shout.",
+			expected: "Write word (up) to shout.",
+		},
+		{
+			name:     "Escaped number modifier",
+			input:    "Use 1A \\(hex) for hexadecimal.",
+			expected: "Use 1A (hex) for hexadecimal.",
+		},
+		{
+			name:     "Escaped next to a real modifier",
+			input:    "the \\(cap) tag capitalizes words (cap) nicely",
+			expected: "the (cap) tag capitalizes Words nicely",
+		},
+		{
+			name:     "Escaped tags are not counted as words",
+			input:    "one two \\(low) (up, 2) three",
+			expected: "ONE TWO (low) three",
+		},
+		{
+			name:     "Doubled parentheses",
+			input:    "Write word ((up)) to shout, or ((Low|cap, 2)) for two.",
+			expected: "Write word (up) to shout, or (Low|cap, 2) for two.",
+		},
+		{
+			name:     "Doubled parentheses around other text stay",
+			input:    "see ((note)) here (up)",
+			expected: "see ((note)) HERE",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := processor.ProcessText(tt.input)
+			if result != tt.expected {
+				t.Errorf("\nInput:    %q\nExpected: %q\nGot:      %q", tt.input, tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestWordCountPolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   string
+		input    string
+		expected string
+	}{
+		{
+			name:     "Default skips numbers",
+			input:    "buy 3 red apples (up, 2)",
+			expected: "buy 3 RED APPLES",
+		},
+		{
+			name:     "Numbers counted",
+			policy:   "numbers",
+			input:    "buy 3 red apples (up, 3)",
+			expected: "buy 3 RED APPLES",
+		},
+		{
+			name:     "Compound counts once by default",
+			input:    "a well-known fact (up, 3)",
+			expected: "A WELL-KNOWN FACT",
+		},
+		{
+			name:     "Compound parts counted separately",
+			policy:   "compounds",
+			input:    "a well-known fact (up, 3)",
+			expected: "a WELL-KNOWN FACT",
+		},
+		{
+			name:     "Punctuation tokens counted",
+			policy:   "punctuation",
+			input:    "one two ^ three (up, 2)",
+			expected: "one two ^ THREE",
+		},
+		{
+			name:     "Non-Latin words count",
+			input:    "café à la (up, 2)",
+			expected: "café À LA",
+		},
+		{
+			name:     "Count crosses sentences by default",
+			input:    "It ends. new start (up, 3)",
+			expected: "It ENDS. NEW START",
+		},
+		{
+			name:     "Count stops at the sentence",
+			policy:   "sentence",
+			input:    "It ends. new start (up, 3)",
+			expected: "It ends. NEW START",
+		},
+		{
+			name:     "Count stops at the line",
+			policy:   "line",
+			input:    "first line\nsecond one (up, 3)",
+			expected: "first line SECOND ONE",
+		},
+		{
+			name:     "Forward count stops at the sentence",
+			policy:   "sentence",
+			input:    "(up, +4) short one. next words",
+			expected: "SHORT ONE. next words",
+		},
+	}
+
+	defer processor.SetOptions(processor.DefaultOptions())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, err := processor.ParseWordCountPolicy(tt.policy)
+			if err != nil {
+				t.Fatalf("ParseWordCountPolicy(%q) error: %v", tt.policy, err)
+			}
+			opts := processor.DefaultOptions()
+			opts.WordCount = policy
+			processor.SetOptions(opts)
+			result := processor.ProcessText(tt.input)
+			if result != tt.expected {
+				t.Errorf("Input: %q\nExpected: %q\nGot:      %q", tt.input, tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestWordCountPolicyReported(t *testing.T) {
+	defer processor.SetOptions(processor.DefaultOptions())
+
+	result := processor.ProcessTextWithInfo("make this (up, 2) text")
+	if !strings.Contains(result, "Applied up transformation to 2 words (numbers excluded)") {
+		t.Errorf("Default policy missing from report:\n%s", result)
+	}
+
+	opts := processor.DefaultOptions()
+	opts.WordCount, _ = processor.ParseWordCountPolicy("numbers,sentence")
+	processor.SetOptions(opts)
+	result = processor.ProcessTextWithInfo("make this (up, 2) text")
+	if !strings.Contains(result, "(numbers counted, within the sentence)") {
+		t.Errorf("Configured policy missing from report:\n%s", result)
+	}
+
+	if _, err := processor.ParseWordCountPolicy("numbers,everything"); err == nil {
+		t.Error("Expected an error for an unknown setting")
+	}
+}
+
+func TestCaseExceptions(t *testing.T) {
+	tests := []struct {
+		name       string
+		exceptions []string
+		input      string
+		expected   string
+	}{
+		{
+			name:     "Brand keeps inner capital under cap",
+			input:    "my iphone (cap) broke",
+			expected: "my iPhone broke",
+		},
+		{
+			name:     "Acronym survives low",
+			input:    "THE NASA LAUNCH (low, 3) today",
+			expected: "the NASA launch today",
+		},
+		{
+			name:     "Name with inner capital",
+			input:    "lunch at mcdonald (cap)",
+			expected: "lunch at McDonald",
+		},
+		{
+			name:     "Possessive and punctuation kept",
+			input:    "the nasa's, (up) (low) budget",
+			expected: "the NASA's, budget",
+		},
+		{
+			name:     "Sentence case keeps acronyms",
+			input:    "THE FBI FILE (sentence, 3)",
+			expected: "The FBI file",
+		},
+		{
+			name:     "Apostrophe name",
+			input:    "mr o'neil (cap) called",
+			expected: "mr O'Neil called",
+		},
+		{
+			name:     "Contraction is not a name",
+			input:    "don't (cap) stop",
+			expected: "Don't stop",
+		},
+		{
+			name:     "O'clock stays lower-case after the apostrophe",
+			input:    "five o'clock (cap)",
+			expected: "five O'clock",
+		},
+		{
+			name:     "Hyphenated compound",
+			input:    "a well-known (cap) fact",
+			expected: "a Well-Known fact",
+		},
+		{
+			name:     "Identifiers ignore the dictionary",
+			input:    "parse json api (pascal, 3)",
+			expected: "ParseJsonApi",
+		},
+		{
+			name:       "Project-level exception",
+			exceptions: []string{"gRPC"},
+			input:      "use GRPC (low) here",
+			expected:   "use gRPC here",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := processor.DefaultOptions()
+			opts.CaseExceptions = tt.exceptions
+			processor.SetOptions(opts)
+			defer processor.SetOptions(processor.DefaultOptions())
+
+			result := processor.ProcessText(tt.input)
+			if result != tt.expected {
+				t.Errorf("\nInput:    %q\nExpected: %q\nGot:      %q", tt.input, tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestSentenceCapitalization(t *testing.T) {
+	opts := processor.DefaultOptions()
+	opts.AutoCapitalize = true
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "First word of every sentence",
+			input:    "it works. does it? yes! it does.",
+			expected: "It works. Does it? Yes! It does.",
+		},
+		{
+			name:     "Pronoun I and its contractions",
+			input:    "i think i'm right, and i know it.",
+			expected: "I think I'm right, and I know it.",
+		},
+		{
+			name:     "List markers keep their case",
+			input:    "(i) first item. (ii) second, or (b) and (i) here.",
+			expected: "(i) first item. (ii) second, or (b) and (i) here.",
+		},
+		{
+			name:     "Abbreviations do not end a sentence",
+			input:    "ask dr. smith, e.g. today. the U.S. team won.",
+			expected: "Ask dr. smith, e.g. today. The U.S. team won.",
+		},
+		{
+			name:     "Abbreviations that are also words need context",
+			input:    "see no. 5 on p. 12. she said no. then she left.",
+			expected: "See no. 5 on p. 12. She said no. Then she left.",
+		},
+		{
+			name:     "Ordinary words end a sentence",
+			input:    "we took the first st. then mar. rang, and plan p. it was al. so was co.",
+			expected: "We took the first st. Then mar. Rang, and plan p. It was al. So was co.",
+		},
+		{
+			name:     "Decimals do not end a sentence",
+			input:    "pi is 3.14 or so. that is enough.",
+			expected: "Pi is 3.14 or so. That is enough.",
+		},
+		{
+			name:     "Closing quotes before the next sentence",
+			input:    `he said "stop." then he left.`,
+			expected: `He said "stop." Then he left.`,
+		},
+		{
+			name:     "Ellipsis only ends a sentence before a capital",
+			input:    "wait... for it. well... No.",
+			expected: "Wait... for it. Well... No.",
+		},
+		{
+			name:     "Opening quote at sentence start",
+			input:    `done. "next one" please.`,
+			expected: `Done. "Next one" please.`,
+		},
+		{
+			name:     "i.e. is not the pronoun",
+			input:    "one thing, i.e. this one.",
+			expected: "One thing, i.e. this one.",
+		},
+		{
+			name:     "Rule can be disabled by directive",
+			input:    "(go-reloaded:disable capitalization) keep it lower. and this.",
+			expected: "keep it lower. and this.",
+		},
+	}
+
+	processor.SetOptions(opts)
+	defer processor.SetOptions(processor.DefaultOptions())
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := processor.ProcessText(tt.input)
+			if result != tt.expected {
+				t.Errorf("\nInput:    %q\nExpected: %q\nGot:      %q", tt.input, tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestSentenceBoundaries(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []int
+	}{
+		{"One. Two! Three? Four", []int{5, 10, 17}},
+		{"Dr. Smith met Mr. Jones.", nil},
+		{"No. 5 and p. 12 on Mar. 3, by Smith et al. Jones & Co. Ltd of St. Paul", nil},
+		{"no. 'quoted' start.", []int{4}},
+		{"Say no. Go to St. Paul st. Then stop", []int{8, 27}},
+		{"It costs 3.50 today. Fine", []int{21}},
+		{"He said \"hi.\" She left", []int{14}},
+		{"first line\n\nsecond para", []int{12}},
+	}
+
+	for _, tt := range tests {
+		got := processor.SentenceBoundaries(tt.input)
+		if fmt.Sprint(got) != fmt.Sprint(tt.expected) {
+			t.Errorf("SentenceBoundaries(%q)\nExpected: %v\nGot:      %v", tt.input, tt.expected, got)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright (c) 2025 Spiros Nikoloudakis
+// Licensed under MIT License - see LICENSE file for details
+
+package tests
+
+import (
+	"go-reloaded/internal/processor"
+	"strings"
+	"testing"
+)
+
+func TestDiagnostics(t *testing.T) {
+	defer processor.SetOptions(processor.DefaultOptions())
+
+	tests := []struct {
+		name      string
+		wordCount string
+		input     string
+		expected  []string
+	}{
+		{
+			name:     "Misspelled modifier gets a suggestion",
+			input:    "make it loud (upp)",
+			expected: []string{"1:14: warning: unknown modifier (upp) - did you mean (up)?"},
+		},
+		{
+			name:     "Swapped letters still suggest the modifier",
+			input:    "quiet now (lwo)",
+			expected: []string{"1:11: warning: unknown modifier (lwo) - did you mean (low)?"},
+		},
+		{
+			name:     "Short words one letter away from a modifier are not flagged",
+			input:    "(see) the (bit) here, (hey) (or) (pu)",
+			expected: []string{"1:34: warning: unknown modifier (pu) - did you mean (up)?"},
+		},
+		{
+			name:     "Ordinary parenthesised words are not flagged",
+			input:    "see the chart (Table) and the note (ok)",
+			expected: nil,
+		},
+		{
+			name:  "Invalid digits for the base",
+			input: "GH (hex) and 23 (bin)",
+			expected: []string{
+				"1:4: warning: 'GH' is not a valid hexadecimal number, so (hex) is left unconverted",
+				"1:17: warning: '23' is not a valid binary number, so (bin) is left unconverted",
+			},
+		},
+		{
+			name:     "Valid literals are silent",
+			input:    "1E (hex) and 10 (bin)",
+			expected: nil,
+		},
+		{
+			name:     "Zero count",
+			input:    "nothing here (cap, 0)",
+			expected: []string{"1:14: warning: (cap, 0) has a count of zero and changes nothing"},
+		},
+		{
+			name:     "Count larger than the available words",
+			input:    "only two words here (up, 5) total",
+			expected: []string{"1:21: info: (up, 5) asks for 5 words but only 4 are available"},
+		},
+		{
+			name:     "Forward count larger than the words after",
+			input:    "(up, +3) one two",
+			expected: []string{"1:1: info: (up, +3) asks for 3 words but only 2 are available"},
+		},
+		{
+			name:     "One available word",
+			input:    "alone (up, 2)",
+			expected: []string{"1:7: info: (up, 2) asks for 2 words but only 1 is available"},
+		},
+		{
+			name:     "A word right before the tag counts",
+			input:    "one two(up, 3)",
+			expected: []string{"1:8: info: (up, 3) asks for 3 words but only 2 are available"},
+		},
+		{
+			name:      "Counts stop at the sentence",
+			wordCount: "sentence",
+			input:     "First words here. Then two (up, 3) and (up, +3) one two. three four",
+			expected: []string{
+				"1:28: info: (up, 3) asks for 3 words but only 2 are available",
+				"1:40: info: (up, +3) asks for 3 words but only 2 are available",
+			},
+		},
+		{
+			name:      "Counts stop at the line",
+			wordCount: "line",
+			input:     "a b\nc (up, 2)",
+			expected:  []string{"2:3: info: (up, 2) asks for 2 words but only 1 is available"},
+		},
+		{
+			name:     "Positions count lines and columns",
+			input:    "first line\nsecond (cpa)",
+			expected: []string{"2:8: warning: unknown modifier (cpa) - did you mean (cap)?"},
+		},
+		{
+			name:     "Narrow wrap width",
+			input:    "tiny lines (wrap, 4)",
+			expected: []string{"1:12: warning: (wrap, 4) is narrower than 10 columns - wrapping at 10"},
+		},
+		{
+			name:     "Escaped and verbatim tags are ignored",
+			input:    "write \\(upp) here (go-reloaded:off) and (cap, 0) (go-reloaded:on)",
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := processor.DefaultOptions()
+			opts.WordCount, _ = processor.ParseWordCountPolicy(tt.wordCount)
+			processor.SetOptions(opts)
+
+			var got []string
+			for _, d := range processor.Diagnose(tt.input) {
+				got = append(got, d.String())
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.expected, "\n") {
+				t.Errorf("Diagnose(%q)\nExpected: %q\nGot:      %q", tt.input, tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestDiagnosticsDoNotChangeOutput(t *testing.T) {
+	input := "make it loud (upp) and nothing (cap, 0)"
+	before := processor.ProcessText(input)
+	processor.Diagnose(input)
+	if after := processor.ProcessText(input); after != before {
+		t.Errorf("Diagnose changed processing result: %q != %q", after, before)
+	}
+}
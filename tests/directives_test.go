@@ -0,0 +1,108 @@
+// Copyright (c) 2025 Spiros Nikoloudakis
+// Licensed under MIT License - see LICENSE file for details
+
+package tests
+
+import (
+	"go-reloaded/internal/processor"
+	"go-reloaded/internal/validator"
+	"strings"
+	"testing"
+)
+
+func TestIgnoreDirectives(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "Off and on region is kept verbatim",
+			input:    "Fix a apple , please (go-reloaded:off) keep a apple , AS IS (up) (go-reloaded:on) and this (up) too",
+			expected: "Fix an apple, please keep a apple , AS IS (up) and THIS too",
+		},
+		{
+			name:     "Off region on its own lines",
+			input:    "Before it , ok\n(go-reloaded:off)\nraw ' text  here\n(go-reloaded:on)\nafter it , ok",
+			expected: "Before it, ok\nraw ' text  here\nafter it, ok",
+		},
+		{
+			name:     "Disable specific rules until enabled",
+			input:    "(go-reloaded:disable articles,punctuation) a apple , here (go-reloaded:enable articles,punctuation) a apple , there",
+			expected: "a apple , here an apple, there",
+		},
+		{
+			name:     "Disable next line",
+			input:    "first a apple\n(go-reloaded:disable-next-line articles)\nsecond a apple\nthird a apple",
+			expected: "first an apple\nsecond a apple\nthird an apple",
+		},
+		{
+			name:     "Escaped directive stays as text",
+			input:    "Write \\(go-reloaded:off) to pause rules .",
+			expected: "Write (go-reloaded:off) to pause rules.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := processor.ProcessText(tt.input)
+			if result != tt.expected {
+				t.Errorf("\nInput:    %q\nExpected: %q\nGot:      %q", tt.input, tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestSkippedRegionsReported(t *testing.T) {
+	result := processor.ProcessTextWithInfo("ok (go-reloaded:off) a apple (go-reloaded:on) done (go-reloaded:disable articles) a apple")
+	for _, want := range []string{"• Skipped: Line 1: 7 characters kept verbatim", "• Skipped: Line 1: articles disabled"} {
+		if !strings.Contains(result, want) {
+			t.Errorf("Expected %q in report, got: %q", want, result)
+		}
+	}
+}
+
+func TestDirectiveValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{
+			name:    "Unclosed quote inside verbatim region is allowed",
+			input:   "text (go-reloaded:off) it's 'broken (go-reloaded:on) more",
+			wantErr: "",
+		},
+		{
+			name:    "Unknown directive",
+			input:   "text (go-reloaded:of) more",
+			wantErr: "INVALID_DIRECTIVE",
+		},
+		{
+			name:    "Unknown rule",
+			input:   "text (go-reloaded:disable grammar) more",
+			wantErr: "INVALID_DIRECTIVE",
+		},
+		{
+			name:    "On without off",
+			input:   "text (go-reloaded:on) more",
+			wantErr: "INVALID_DIRECTIVE",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.ValidateInput(tt.input)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error but got: %v", err)
+				}
+				return
+			}
+			validationErr, ok := err.(validator.ValidationError)
+			if !ok || validationErr.Type != tt.wantErr {
+				t.Errorf("Expected %s error, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
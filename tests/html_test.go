@@ -0,0 +1,151 @@
+// Copyright (c) 2025 Spiros Nikoloudakis
+// Licensed under MIT License - see LICENSE file for details
+
+package tests
+
+import (
+	"go-reloaded/internal/htmltext"
+	"go-reloaded/internal/processor"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHTMLTokenizer(t *testing.T) {
+	input := "<p class=\"a > b\">Hi , <b>you</b> &amp; a < b</p><!-- x --><pre>keep ,</pre>"
+	doc := htmltext.Parse(input)
+
+	var raw strings.Builder
+	var texts []string
+	for _, b := range doc.Blocks {
+		raw.WriteString(b.Raw)
+		if b.Kind == htmltext.Text {
+			var prose []string
+			for _, inline := range b.Inlines {
+				if inline.Prose {
+					prose = append(prose, inline.Text)
+				}
+			}
+			texts = append(texts, strings.Join(prose, "|"))
+		}
+	}
+	if raw.String() != input {
+		t.Errorf("Blocks do not add up to the input: %q", raw.String())
+	}
+	if expected := []string{"Hi , |you| | a < b"}; strings.Join(texts, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Text blocks:\nExpected: %q\nGot:      %q", expected, texts)
+	}
+	if mask := doc.Mask(); len(mask) != len(input) || strings.Contains(mask, "class") || strings.Contains(mask, "amp") || !strings.Contains(mask, "a < b") {
+		t.Errorf("Mask kept markup: %q", mask)
+	}
+}
+
+func TestHTMLLargeInput(t *testing.T) {
+	input := "<p>" + strings.Repeat("some <b>text</b> here\n", 20000) + "</p>  \n"
+	start := time.Now()
+	doc := htmltext.Parse(input)
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Parsing %d bytes took %v", len(input), elapsed)
+	}
+
+	var raw strings.Builder
+	for _, b := range doc.Blocks {
+		raw.WriteString(b.Raw)
+	}
+	if raw.String() != input {
+		t.Errorf("Blocks do not add up to the input")
+	}
+}
+
+func TestHTMLMode(t *testing.T) {
+	defer processor.SetOptions(processor.DefaultOptions())
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "Only text nodes change",
+			input:    "<p class=\"a , b\" title='it is a apple'>it is a apple , ok .</p>",
+			expected: "<p class=\"a , b\" title='it is a apple'>it is an apple, ok.</p>",
+		},
+		{
+			name:     "Modifiers count words across inline tags",
+			input:    "<p>it is <b>very nice</b> (up, 2)</p>",
+			expected: "<p>it is <b>VERY NICE</b></p>",
+		},
+		{
+			name:     "Tag just inside an inline element",
+			input:    "<p><em>word (up)</em> and <b>more (cap)</b>.</p>",
+			expected: "<p><em>WORD</em> and <b>More</b>.</p>",
+		},
+		{
+			name:     "Link text is transformed, its URL is not",
+			input:    "<a href=\"/a?b=1&amp;c=2#top\">the docs</a> (cap, 2)",
+			expected: "<a href=\"/a?b=1&amp;c=2#top\">The Docs</a>",
+		},
+		{
+			name:     "Entities are kept as written",
+			input:    "<p>AT&amp;T &copy; 2025 &#8217;s ok .</p>",
+			expected: "<p>AT&amp;T &copy; 2025 &#8217;s ok.</p>",
+		},
+		{
+			name:     "Raw elements are skipped",
+			input:    "<script>if (a < b) { x = 'it ' ; }</script>\n<style>p > a { color : red ; }</style>\n<pre>keep   this , (up)</pre>\n<p>run <code>a , b (up)</code> now ,</p>",
+			expected: "<script>if (a < b) { x = 'it ' ; }</script>\n<style>p > a { color : red ; }</style>\n<pre>keep   this , (up)</pre>\n<p>run <code>a , b (up)</code> now,</p>",
+		},
+		{
+			name:     "Block elements are separate units",
+			input:    "<ul>\n  <li>one</li>\n  <li>two three (up, 2)</li>\n</ul>\n",
+			expected: "<ul>\n  <li>one</li>\n  <li>TWO THREE</li>\n</ul>\n",
+		},
+		{
+			name:     "Line breaks and images separate words",
+			input:    "<p>a<br>b (up)</p>\n<p>one<img src=\"x.png\">two<wbr>three (cap, 2)</p>\n<p>x<br/> y (up)</p>",
+			expected: "<p>a<br>B</p>\n<p>one<img src=\"x.png\">Two<wbr>Three</p>\n<p>x<br/> Y</p>",
+		},
+		{
+			name:     "Other inline tags stay inside the word",
+			input:    "<p>a<b>b</b> (up)</p>",
+			expected: "<p>A<b>B</b></p>",
+		},
+		{
+			name:     "Tag spacing is never normalized",
+			input:    "<p >hello</p >\n<!-- a , comment -->",
+			expected: "<p >hello</p >\n<!-- a , comment -->",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := processor.DefaultOptions()
+			opts.Format = processor.FormatHTML
+			opts.TightOperators = processor.AllOperators
+			processor.SetOptions(opts)
+			if result := processor.ProcessText(tt.input); result != tt.expected {
+				t.Errorf("Input: %q\nExpected: %q\nGot:      %q", tt.input, tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestFormatForFile(t *testing.T) {
+	tests := map[string]string{
+		"README.md":      processor.FormatMarkdown,
+		"notes.markdown": processor.FormatMarkdown,
+		"index.HTML":     processor.FormatHTML,
+		"page.htm":       processor.FormatHTML,
+		"main.go":        processor.FormatGo,
+		"deploy.sh":      processor.FormatHash,
+		"config.yaml":    processor.FormatHash,
+		"app.ts":         processor.FormatSlash,
+		"input.txt":      processor.FormatText,
+		"no-extension":   processor.FormatText,
+	}
+	for name, expected := range tests {
+		if got := processor.FormatForFile(name); got != expected {
+			t.Errorf("FormatForFile(%q) = %q, expected %q", name, got, expected)
+		}
+	}
+}
@@ -0,0 +1,217 @@
+// Copyright (c) 2025 Spiros Nikoloudakis
+// Licensed under MIT License - see LICENSE file for details
+
+package tests
+
+import (
+	"go-reloaded/internal/markdown"
+	"go-reloaded/internal/processor"
+	"strings"
+	"testing"
+	"time"
+)
+
+// markdownSample touches every kind of block the Markdown parser knows
+const markdownSample = "---\ntitle: \"it 's (up) draft\"\n---\n" +
+	"# Release   notes (up) #\n\n" +
+	"Use `go run . (up)` or see [the docs](https://example.com/a_b \"Docs\") .  \nSecond line &amp; <b>bold</b> .\n\n" +
+	"- first item , with *emphasis*\n  continued here\n- [ ] a task\n1. numbered\n\n" +
+	"> quoted , text\n> more\n\n" +
+	"```go\nx := \"unclosed (up)\n```\n\n" +
+	"    indented code ,  (up)\n\n" +
+	"| Name | Value |\n|------|:-----:|\n| a `|` b ,c | it (up) |\n\n" +
+	"<div>\nraw  html (up)\n</div>\n\n***\n[ref]: http://example.com/x_y\n"
+
+func TestMarkdownParseRoundTrip(t *testing.T) {
+	doc := markdown.Parse(markdownSample)
+	if got := doc.Rewrite(func(b *markdown.Block) string { return b.Content }); got != markdownSample {
+		t.Errorf("Rewrite without changes altered the document:\n%q", got)
+	}
+	if mask := doc.Mask(); len(mask) != len(markdownSample) || strings.Count(mask, "\n") != strings.Count(markdownSample, "\n") {
+		t.Errorf("Mask changed positions: %q", mask)
+	}
+
+	var kinds []markdown.Kind
+	for _, b := range doc.Blocks {
+		if b.Kind != markdown.Verbatim {
+			kinds = append(kinds, b.Kind)
+		}
+	}
+	expected := []markdown.Kind{
+		markdown.Heading, markdown.Paragraph, markdown.Paragraph, markdown.Paragraph, markdown.Paragraph, markdown.Paragraph,
+		markdown.TableCell, markdown.TableCell, markdown.TableCell, markdown.TableCell,
+	}
+	if len(kinds) != len(expected) {
+		t.Fatalf("Expected %d prose blocks, got %v", len(expected), kinds)
+	}
+	for i := range kinds {
+		if kinds[i] != expected[i] {
+			t.Errorf("Prose block %d: expected kind %d, got %d", i, expected[i], kinds[i])
+		}
+	}
+}
+
+func TestMarkdownInlines(t *testing.T) {
+	doc := markdown.Parse("Try `a (up)` and [text](http://x.io/a_b) or ![alt](i.png) with **bold** &copy; <i>x</i> \\* 2 * 3\n")
+	var prose, syntax []string
+	for _, inline := range doc.Blocks[0].Inlines() {
+		if inline.Prose {
+			prose = append(prose, inline.Text)
+		} else {
+			syntax = append(syntax, inline.Text)
+		}
+	}
+	expectedSyntax := []string{"`a (up)`", "[", "](http://x.io/a_b)", "![", "](i.png)", "**", "**", "&copy;", "<i>", "</i>", "\\*"}
+	if strings.Join(syntax, "|") != strings.Join(expectedSyntax, "|") {
+		t.Errorf("Syntax inlines:\nExpected: %q\nGot:      %q", expectedSyntax, syntax)
+	}
+	if got := strings.Join(prose, "|"); !strings.HasSuffix(got, " 2 * 3") {
+		t.Errorf("A spaced * should stay prose, got %q", got)
+	}
+}
+
+func TestMarkdownLargeInput(t *testing.T) {
+	inputs := map[string]string{
+		"Long paragraph":    strings.Repeat("some *text* here\n", 25000),
+		"Unclosed brackets": strings.Repeat("[a ", 40000) + "\n",
+		"Backtick runs":     strings.Repeat("a `` b ` ", 20000) + "\n",
+		"Code blocks":       strings.Repeat("    code\n", 40000),
+	}
+	for name, input := range inputs {
+		t.Run(name, func(t *testing.T) {
+			start := time.Now()
+			doc := markdown.Parse(input)
+			for _, b := range doc.Blocks {
+				if b.Kind != markdown.Verbatim {
+					b.Inlines()
+				}
+			}
+			if elapsed := time.Since(start); elapsed > 2*time.Second {
+				t.Errorf("Parsing %d bytes took %v", len(input), elapsed)
+			}
+			if got := doc.Rewrite(func(b *markdown.Block) string { return b.Content }); got != input {
+				t.Errorf("Rewrite without changes altered the document")
+			}
+		})
+	}
+}
+
+func TestMarkdownMode(t *testing.T) {
+	defer processor.SetOptions(processor.DefaultOptions())
+
+	opts := processor.DefaultOptions()
+	opts.Format = processor.FormatMarkdown
+	processor.SetOptions(opts)
+
+	result := processor.ProcessText(markdownSample)
+	if strings.HasPrefix(result, "ERROR") {
+		t.Fatalf("Unexpected error: %s", result)
+	}
+
+	kept := []string{
+		"---\ntitle: \"it 's (up) draft\"\n---\n",
+		"`go run . (up)`",
+		"](https://example.com/a_b \"Docs\")",
+		"&amp; <b>bold</b>",
+		"```go\nx := \"unclosed (up)\n```\n",
+		"    indented code ,  (up)\n",
+		"|------|:-----:|\n| a `|` b,c |",
+		"<div>\nraw  html (up)\n</div>\n",
+		"[ref]: http://example.com/x_y\n",
+		"- [ ] a task\n1. numbered\n",
+	}
+	for _, part := range kept {
+		if !strings.Contains(result, part) {
+			t.Errorf("Expected %q to be kept byte-for-byte in:\n%s", part, result)
+		}
+	}
+
+	changed := []string{
+		"# Release NOTES #\n",
+		"[the docs](https://example.com/a_b \"Docs\").  \nSecond line",
+		"<b>bold</b>.\n",
+		"- first item, with *emphasis*\n  continued here\n",
+		"> quoted, text\n> more\n",
+		"| IT |",
+	}
+	for _, part := range changed {
+		if !strings.Contains(result, part) {
+			t.Errorf("Expected %q in:\n%s", part, result)
+		}
+	}
+	if !strings.HasSuffix(result, "\n") {
+		t.Errorf("Expected the final newline to be kept")
+	}
+}
+
+func TestMarkdownModeProse(t *testing.T) {
+	defer processor.SetOptions(processor.DefaultOptions())
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "Modifiers count words across emphasis",
+			input:    "it is **very nice** (up, 2)",
+			expected: "it is **VERY NICE**",
+		},
+		{
+			name:     "Tag just inside emphasis",
+			input:    "some **bold (up)** and _word (cap)_ text",
+			expected: "some **BOLD** and _Word_ text",
+		},
+		{
+			name:     "Link text is prose",
+			input:    "see [the docs](http://x.io/a_b) (cap, 2)",
+			expected: "see [The Docs](http://x.io/a_b)",
+		},
+		{
+			name:     "Tight operators leave emphasis alone",
+			input:    "a *b* and _c_ here",
+			expected: "a *b* and _c_ here",
+		},
+		{
+			name:     "Hard line breaks are kept",
+			input:    "first line ,  \nsecond line",
+			expected: "first line,  \nsecond line",
+		},
+		{
+			name:     "Verbatim region spans paragraphs",
+			input:    "(go-reloaded:off)\nkeep  this ,\n\nand  this ,\n(go-reloaded:on)\nfix this ,",
+			expected: "keep  this ,\n\nand  this ,\nfix this,",
+		},
+		{
+			name:     "Unclosed quotes in code do not fail validation",
+			input:    "run `echo \"hi` now\n\n```\nprint('x\n```",
+			expected: "run `echo \"hi` now\n\n```\nprint('x\n```",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := processor.DefaultOptions()
+			opts.Format = processor.FormatMarkdown
+			opts.TightOperators = processor.AllOperators
+			processor.SetOptions(opts)
+			if result := processor.ProcessText(tt.input); result != tt.expected {
+				t.Errorf("Input: %q\nExpected: %q\nGot:      %q", tt.input, tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestMarkdownDiagnosticsSkipCode(t *testing.T) {
+	defer processor.SetOptions(processor.DefaultOptions())
+
+	opts := processor.DefaultOptions()
+	opts.Format = processor.FormatMarkdown
+	processor.SetOptions(opts)
+
+	input := "call `f (upp)` here\n\n```\n(cpa)\n```\n\nbut not (lwo)"
+	diagnostics := processor.Diagnose(input)
+	if len(diagnostics) != 1 || diagnostics[0].Line != 7 {
+		t.Errorf("Expected one diagnostic on line 7, got %v", diagnostics)
+	}
+}
@@ -0,0 +1,379 @@
+// Copyright (c) 2025 Spiros Nikoloudakis
+// Licensed under MIT License - see LICENSE file for details
+
+package tests
+
+import (
+	"go-reloaded/internal/processor"
+	"strings"
+	"testing"
+)
+
+func TestFixedWidthConversions(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "Signed byte",
+			input:    "The register reads FF (hex, s8) today.",
+			expected: "The register reads -1 today.",
+		},
+		{
+			name:     "Positive signed byte",
+			input:    "Value 7F (hex, s8) is the maximum.",
+			expected: "Value 127 is the maximum.",
+		},
+		{
+			name:     "Signed binary word",
+			input:    "Offset 1111111111111110 (bin, s16) bytes.",
+			expected: "Offset -2 bytes.",
+		},
+		{
+			name:     "Unsigned 32-bit",
+			input:    "Mask FFFFFFFF (hex, u32) applied.",
+			expected: "Mask 4294967295 applied.",
+		},
+		{
+			name:     "Signed 64-bit",
+			input:    "Sentinel FFFFFFFFFFFFFFFF (hex, s64) found.",
+			expected: "Sentinel -1 found.",
+		},
+		{
+			name:     "Prefixed literals",
+			input:    "Add 0x1A (hex) and 0b101 (bin) together.",
+			expected: "Add 26 and 5 together.",
+		},
+		{
+			name:     "Overflow is left unchanged",
+			input:    "Too big 1FF (hex, s8) here.",
+			expected: "Too big 1FF (hex, s8) here.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := processor.ProcessText(tt.input)
+			if result != tt.expected {
+				t.Errorf("\nInput:    %q\nExpected: %q\nGot:      %q", tt.input, tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestFixedWidthOverflowWarning(t *testing.T) {
+	result := processor.ProcessTextWithInfo("Too big 1FF (hex, s8) here.")
+	if !strings.Contains(result, "• Number: Warning: 1FF (hex, s8) overflows 8 bits") {
+		t.Errorf("Expected overflow warning in report, got: %q", result)
+	}
+}
+
+func TestNumberFormattingSign(t *testing.T) {
+	result := processor.ProcessTextWithInfo("Score 5-3 (sep) and -4 (ord) today.")
+	if !strings.HasPrefix(result, "Score 5-3 and -4th today.") {
+		t.Errorf("Unexpected result: %q", result)
+	}
+	if !strings.Contains(result, "Formatted 3 with sep") || !strings.Contains(result, "Formatted -4 with ord") {
+		t.Errorf("Expected the minus after 5 to stay out of the number, got: %q", result)
+	}
+}
+
+func TestNumberFormatting(t *testing.T) {
+	tests := []struct {
+		name     string
+		locale   string
+		input    string
+		expected string
+	}{
+		{
+			name:     "Thousands grouping",
+			input:    "Revenue was 1234567 (sep) dollars.",
+			expected: "Revenue was 1,234,567 dollars.",
+		},
+		{
+			name:     "Rounding",
+			input:    "Pi is about 3.14159 (round, 2) today.",
+			expected: "Pi is about 3.14 today.",
+		},
+		{
+			name:     "Rounding keeps every digit",
+			input:    "Total 12345678901234567.891 (round, 2) and 0.123456789012345678 (pct, 14) here.",
+			expected: "Total 12345678901234567.89 and 12.34567890123457% here.",
+		},
+		{
+			name:     "Rounding halves away from zero",
+			input:    "Values 2.5 (round), -2.345 (round, 2), 9.96 (round, 1) and -0.4 (round).",
+			expected: "Values 3, -2.35, 10.0 and 0.",
+		},
+		{
+			name:     "Ordinals",
+			input:    "She finished 21 (ord) and 12 (ord) and 3 (ord).",
+			expected: "She finished 21st and 12th and 3rd.",
+		},
+		{
+			name:     "Percentages",
+			input:    "Only 0.25 (pct) agreed.",
+			expected: "Only 25% agreed.",
+		},
+		{
+			name:     "Precision over the cap is left unchanged",
+			input:    "About 3.5 (round, 300000000) and 0.5 (pct, 21) here.",
+			expected: "About 3.5 (round, 300000000) and 0.5 (pct, 21) here.",
+		},
+		{
+			name:     "Precision at the cap",
+			input:    "About 0.5 (round, 20) here.",
+			expected: "About 0.50000000000000000000 here.",
+		},
+		{
+			name:     "Conversion then grouping",
+			input:    "Memory FFFFF (hex) (sep) bytes.",
+			expected: "Memory 1,048,575 bytes.",
+		},
+		{
+			name:     "German grouping",
+			locale:   "de",
+			input:    "Es kostet 1234567.5 (sep) Euro.",
+			expected: "Es kostet 1.234.567,5 Euro.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := processor.DefaultOptions()
+			if tt.locale != "" {
+				opts.Locale = tt.locale
+			}
+			processor.SetOptions(opts)
+			defer processor.SetOptions(processor.DefaultOptions())
+
+			result := processor.ProcessText(tt.input)
+			if result != tt.expected {
+				t.Errorf("\nInput:    %q\nExpected: %q\nGot:      %q", tt.input, tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestNumberWords(t *testing.T) {
+	tests := []struct {
+		name       string
+		spellBelow int
+		input      string
+		expected   string
+	}{
+		{
+			name:     "Digits to words",
+			input:    "The answer is 42 (words) exactly.",
+			expected: "The answer is forty-two exactly.",
+		},
+		{
+			name:     "Large number to words",
+			input:    "We counted 1205 (words) votes.",
+			expected: "We counted one thousand two hundred five votes.",
+		},
+		{
+			name:     "Words to digits",
+			input:    "There were twenty one (num) guests.",
+			expected: "There were 21 guests.",
+		},
+		{
+			name:     "Hyphenated words with scale",
+			input:    "It cost three hundred and forty-five (num) dollars.",
+			expected: "It cost 345 dollars.",
+		},
+		{
+			name:     "Smallest int64 to words",
+			input:    "x -9223372036854775808 (words) y",
+			expected: "x minus nine quintillion two hundred twenty-three quadrillion three hundred seventy-two trillion thirty-six billion eight hundred fifty-four million seven hundred seventy-five thousand eight hundred eight y",
+		},
+		{
+			name:     "Quintillion to words",
+			input:    "x 1000000000000000000 (words) y",
+			expected: "x one quintillion y",
+		},
+		{
+			name:     "Quadrillion words to digits",
+			input:    "x two quadrillion five (num) y",
+			expected: "x 2000000000000005 y",
+		},
+		{
+			name:     "Consecutive units are not converted",
+			input:    "one two three (num)",
+			expected: "one two three (num)",
+		},
+		{
+			name:     "Unit after a teen is not converted",
+			input:    "twelve five (num)",
+			expected: "twelve five (num)",
+		},
+		{
+			name:     "Repeated scale is not converted",
+			input:    "one trillion trillion trillion trillion (num)",
+			expected: "one trillion trillion trillion trillion (num)",
+		},
+		{
+			name:     "Scales out of order are not converted",
+			input:    "two thousand million (num)",
+			expected: "two thousand million (num)",
+		},
+		{
+			name:       "Style guide spells out small numbers",
+			spellBelow: 10,
+			input:      "I have 3 cats and 12 dogs.",
+			expected:   "I have three cats and 12 dogs.",
+		},
+		{
+			name:       "Style guide keeps technical numbers",
+			spellBelow: 10,
+			input:      "Use 5 kg of flour, version 2.5 and 1010 (bin) for 3 days.",
+			expected:   "Use 5 kg of flour, version 2.5 and 10 for three days.",
+		},
+		{
+			name:       "Style guide keeps dates and versions",
+			spellBelow: 10,
+			input:      "On May 3, 2024 we shipped version 2 and v 3, due 4 Dec. with 5 fixes.",
+			expected:   "On May 3, 2024 we shipped version 2 and v 3, due 4 Dec. with five fixes.",
+		},
+		{
+			name:       "Style guide leaves conversion results",
+			spellBelow: 10,
+			input:      "Add 7 (hex) to the 2 (up, 2) values.",
+			expected:   "Add 7 TO THE 2 values.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := processor.DefaultOptions()
+			opts.SpellOutBelow = tt.spellBelow
+			processor.SetOptions(opts)
+			defer processor.SetOptions(processor.DefaultOptions())
+
+			result := processor.ProcessText(tt.input)
+			if result != tt.expected {
+				t.Errorf("\nInput:    %q\nExpected: %q\nGot:      %q", tt.input, tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestRomanConversions(t *testing.T) {
+	tests := []struct {
+		name     string
+		strict   bool
+		input    string
+		expected string
+	}{
+		{
+			name:     "Roman to decimal",
+			input:    "See section XIV (roman) for details.",
+			expected: "See section 14 for details.",
+		},
+		{
+			name:     "Decimal to Roman",
+			input:    "Chapter 14 (toroman) begins here.",
+			expected: "Chapter XIV begins here.",
+		},
+		{
+			name:     "Lower-case output",
+			input:    "Clause 1994 (toroman, lower) applies.",
+			expected: "Clause mcmxciv applies.",
+		},
+		{
+			name:     "Lenient additive numeral",
+			input:    "Old clocks show IIII (roman) instead.",
+			expected: "Old clocks show 4 instead.",
+		},
+		{
+			name:     "Strict mode rejects additive numeral",
+			strict:   true,
+			input:    "Old clocks show IIII (roman) instead.",
+			expected: "Old clocks show IIII (roman) instead.",
+		},
+		{
+			name:     "Strict mode rejects invalid subtraction",
+			strict:   true,
+			input:    "Invalid VX (roman) numeral.",
+			expected: "Invalid VX (roman) numeral.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := processor.DefaultOptions()
+			opts.StrictRoman = tt.strict
+			processor.SetOptions(opts)
+			defer processor.SetOptions(processor.DefaultOptions())
+
+			result := processor.ProcessText(tt.input)
+			if result != tt.expected {
+				t.Errorf("\nInput:    %q\nExpected: %q\nGot:      %q", tt.input, tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestCalcModifier(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "Parentheses and precedence",
+			input:    "The total is 3 * (4 + 2) (calc) items.",
+			expected: "The total is 18 items.",
+		},
+		{
+			name:     "Hex and binary literals",
+			input:    "Sum 0x10 + 0b11 (calc) here.",
+			expected: "Sum 19 here.",
+		},
+		{
+			name:     "Power and modulo",
+			input:    "We need 2 ^ 10 % 1000 (calc) slots.",
+			expected: "We need 24 slots.",
+		},
+		{
+			name:     "Decimals",
+			input:    "Each costs 0.1 + 0.2 (calc) dollars.",
+			expected: "Each costs 0.3 dollars.",
+		},
+		{
+			name:     "Sentence-final number is not part of the expression",
+			input:    "It began in 2020. 3 + 4 (calc) teams joined.",
+			expected: "It began in 2020. 7 teams joined.",
+		},
+		{
+			name:     "Expression inside surrounding parentheses",
+			input:    "It works (about 6 / 4 (calc) times) well.",
+			expected: "It works (about 1.5 times) well.",
+		},
+		{
+			name:     "Division by zero is left unchanged",
+			input:    "Bad 1/0 (calc) math.",
+			expected: "Bad 1/0 (calc) math.",
+		},
+		{
+			name:     "Oversized result is left unchanged",
+			input:    "Huge 10 ^ 100 (calc) number.",
+			expected: "Huge 10 ^ 100 (calc) number.",
+		},
+		{
+			name:     "Huge literal is left unchanged",
+			input:    "Huge 99999999999999999999 (calc) number.",
+			expected: "Huge 99999999999999999999 (calc) number.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := processor.ProcessText(tt.input)
+			if result != tt.expected {
+				t.Errorf("\nInput:    %q\nExpected: %q\nGot:      %q", tt.input, tt.expected, result)
+			}
+		})
+	}
+}
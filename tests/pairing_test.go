@@ -0,0 +1,277 @@
+// Copyright (c) 2025 Spiros Nikoloudakis
+// Licensed under MIT License - see LICENSE file for details
+
+package tests
+
+import (
+	"go-reloaded/internal/pairing"
+	"go-reloaded/internal/processor"
+	"go-reloaded/internal/validator"
+	"strings"
+	"testing"
+	"time"
+)
+
+// roleLetters spells each mark's role as one letter: o(pen), c(lose), a(postrophe),
+// u(nclosed) and m (unmatched)
+func roleLetters(tree *pairing.Tree) string {
+	var letters strings.Builder
+	for _, mark := range tree.Marks {
+		letters.WriteByte("ocaum"[mark.Role])
+	}
+	return letters.String()
+}
+
+func TestPairingRoles(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "Contraction", input: "don't 'stop'", expected: "aoc"},
+		{name: "Split contraction", input: "don 't stop", expected: "a"},
+		{name: "Quoted letter", input: "the 'd' key", expected: "oc"},
+		{name: "Elision", input: "'twas the night", expected: "a"},
+		{name: "Rock 'n' roll", input: "'rock 'n' roll'", expected: "oaac"},
+		{name: "Decade", input: "the '90s were 'fun'", expected: "aoc"},
+		{name: "Plural possessive", input: "the students' books, 'quoted'", expected: "aoc"},
+		{name: "Plural possessive inside a quote", input: "'the students' books'", expected: "oac"},
+		{name: "Quote ending in s", input: "'yes' and 'no'", expected: "ococ"},
+		{name: "Dropped letter", input: "goin' home", expected: "a"},
+		{name: "Nested quotes", input: `"he said 'hi'"`, expected: "oocc"},
+		{name: "Three levels of nesting", input: `"a 'b "c" d' e"`, expected: "oooccc"},
+		{name: "Spaced quotes", input: "' hello '", expected: "oc"},
+		{name: "Brackets and quotes", input: `(a [b {"c"}])`, expected: "oooocccc"},
+		{name: "Unclosed quote inside brackets", input: "(it is 'open)", expected: "ouc"},
+		{name: "Unmatched bracket", input: "a) b", expected: "m"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := roleLetters(pairing.Parse(tt.input)); got != tt.expected {
+				t.Errorf("Input: %q\nExpected roles: %q\nGot:            %q", tt.input, tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestPairingTree(t *testing.T) {
+	text := `"he said 'hi (up)' to (them)"`
+	tree := pairing.Parse(text)
+	if len(tree.Regions) != 1 {
+		t.Fatalf("Expected 1 top-level region, got %d", len(tree.Regions))
+	}
+	outer := tree.Regions[0]
+	if got := outer.Inner(text); got != "he said 'hi (up)' to (them)" {
+		t.Errorf("Outer region content: %q", got)
+	}
+	if len(outer.Children) != 2 || !outer.Children[0].IsQuote() || outer.Children[1].IsQuote() {
+		t.Fatalf("Expected a quote and a bracket inside the outer region, got %d children", len(outer.Children))
+	}
+	if depth := tree.MaxDepth(); depth != 3 {
+		t.Errorf("Expected depth 3, got %d", depth)
+	}
+
+	upper := tree.Rewrite(func(r *pairing.Region, inner string) string {
+		return r.Wrap(strings.ToUpper(inner))
+	})
+	if upper != `"HE SAID 'HI (UP)' TO (THEM)"` {
+		t.Errorf("Rewrite: %q", upper)
+	}
+}
+
+func TestPairingLargeInput(t *testing.T) {
+	inputs := map[string]string{
+		"Deeply nested brackets":                    strings.Repeat("(", 40000) + strings.Repeat(")", 40000),
+		"Many closed brackets inside unclosed ones": strings.Repeat("(", 20000) + strings.Repeat("()", 20000),
+		"Many quotes ending in s":                   strings.Repeat("'s' ", 50000),
+	}
+	for name, input := range inputs {
+		t.Run(name, func(t *testing.T) {
+			start := time.Now()
+			tree := pairing.Parse(input)
+			validator.ValidateInput(input)
+			if elapsed := time.Since(start); elapsed > 2*time.Second {
+				t.Errorf("Parsing and validating %d bytes took %v", len(input), elapsed)
+			}
+			if tree.MaxDepth() == 0 {
+				t.Errorf("Expected regions in the tree")
+			}
+		})
+	}
+}
+
+func TestPairingConsumers(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "Elision is not an unclosed quote",
+			input:    "'twas the night",
+			expected: "'twas the night",
+		},
+		{
+			name:     "Rock 'n' roll inside a quote",
+			input:    "she said ' rock 'n' roll '",
+			expected: "she said 'rock 'n' roll'",
+		},
+		{
+			name:     "Plural possessive before a quote",
+			input:    "the students' books, ' quoted '",
+			expected: "the students' books, 'quoted'",
+		},
+		{
+			name:     "Nested quotes with case modifiers",
+			input:    `"he said 'hi (up)' (cap)"`,
+			expected: `"He said 'hi'"`,
+		},
+		{
+			name:     "Nested brackets with case modifiers",
+			input:    "( nested ( deep (up) ) )",
+			expected: "(nested (DEEP))",
+		},
+		{
+			name:     "Contraction inside a quoted modifier",
+			input:    "'don't stop (up)'",
+			expected: "'DON'T STOP'",
+		},
+		{
+			name:     "Three levels of nested quotes",
+			input:    `he wrote "she said 'he yelled "stop (up)" loudly' twice" ok`,
+			expected: `he wrote "she said 'he yelled "STOP" loudly' twice" ok`,
+		},
+		{
+			name:     "Nested spacing",
+			input:    `" he said ' hi ' "`,
+			expected: `"he said 'hi'"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validator.ValidateInput(tt.input); err != nil {
+				t.Fatalf("Unexpected validation error: %v", err)
+			}
+			if result := processor.ProcessText(tt.input); result != tt.expected {
+				t.Errorf("Input: %q\nExpected: %q\nGot:      %q", tt.input, tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestQuoteStyleModifiers(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "Single to double quotes",
+			input:    "'hello' (dq)",
+			expected: `"hello"`,
+		},
+		{
+			name:     "Double to single quotes",
+			input:    `say "hello" (sq) now`,
+			expected: "say 'hello' now",
+		},
+		{
+			name:     "Modifier inside the span",
+			input:    `"hello (sq)"`,
+			expected: "'hello'",
+		},
+		{
+			name:     "Nested quotes alternate",
+			input:    `'he said "hi"' (dq)`,
+			expected: `"he said 'hi'"`,
+		},
+		{
+			name:     "Combined with a case modifier",
+			input:    "'hi (up)' (dq)",
+			expected: `"HI"`,
+		},
+		{
+			name:     "Tag without a quoted span is left in place",
+			input:    "hello (dq) world",
+			expected: "hello (dq) world",
+		},
+		{
+			name:     "Apostrophes are not converted",
+			input:    "'don't go' (dq)",
+			expected: `"don't go"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := processor.ProcessText(tt.input); result != tt.expected {
+				t.Errorf("Input: %q\nExpected: %q\nGot:      %q", tt.input, tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestQuoteModifierWithoutQuoteWarning(t *testing.T) {
+	result := processor.ProcessTextWithInfo("hello (sq) world")
+	if !strings.Contains(result, "• Quotes: Warning: (sq) has no quoted text to convert - left unchanged") {
+		t.Errorf("Expected warning in report, got: %q", result)
+	}
+}
+
+func TestHouseQuoteStyle(t *testing.T) {
+	defer processor.SetOptions(processor.DefaultOptions())
+
+	tests := []struct {
+		name     string
+		style    string
+		input    string
+		expected string
+	}{
+		{
+			name:     "Double outside, single inside",
+			style:    processor.QuoteStyleDouble,
+			input:    `'outer "inner 'deep' x" y' and don't`,
+			expected: `"outer 'inner "deep" x' y" and don't`,
+		},
+		{
+			name:     "Single outside, double inside",
+			style:    processor.QuoteStyleSingle,
+			input:    `"he said 'hi'"`,
+			expected: `'he said "hi"'`,
+		},
+		{
+			name:     "Possessives are kept",
+			style:    processor.QuoteStyleDouble,
+			input:    "'the students' books'",
+			expected: `"the students' books"`,
+		},
+		{
+			name:     "Three levels are stable when processed again",
+			style:    processor.QuoteStyleDouble,
+			input:    `say "a 'b "c" d' e" ok`,
+			expected: `say "a 'b "c" d' e" ok`,
+		},
+		{
+			name:     "No style keeps quotes as written",
+			input:    `'a "b" c'`,
+			expected: `'a "b" c'`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := processor.DefaultOptions()
+			opts.QuoteStyle = tt.style
+			processor.SetOptions(opts)
+			result := processor.ProcessText(tt.input)
+			if result != tt.expected {
+				t.Errorf("Input: %q\nExpected: %q\nGot:      %q", tt.input, tt.expected, result)
+			}
+			if again := processor.ProcessText(result); again != result {
+				t.Errorf("Output changed when processed again:\nFirst:  %q\nSecond: %q", result, again)
+			}
+		})
+	}
+}
@@ -0,0 +1,404 @@
+// Copyright (c) 2025 Spiros Nikoloudakis
+// Licensed under MIT License - see LICENSE file for details
+
+package tests
+
+import (
+	"go-reloaded/internal/processor"
+	"strings"
+	"testing"
+)
+
+func TestProtectedTokens(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "Ampersand keeps its spaces",
+			input:    "rock & roll , baby",
+			expected: "rock & roll, baby",
+		},
+		{
+			name:     "Math expression",
+			input:    "we know 5 - 3 = 2 , right",
+			expected: "we know 5 - 3 = 2, right",
+		},
+		{
+			name:     "Relative path",
+			input:    "see ./docs/help.md , then run",
+			expected: "see ./docs/help.md, then run",
+		},
+		{
+			name:     "URL with query and trailing period",
+			input:    "visit https://example.com/a?b=1 . Thanks",
+			expected: "visit https://example.com/a?b=1. Thanks",
+		},
+		{
+			name:     "Email address",
+			input:    "write to jane.doe@example.org , please",
+			expected: "write to jane.doe@example.org, please",
+		},
+		{
+			name:     "Version number and decimal",
+			input:    "v1.2.3 is out , and pi is 3.14 !",
+			expected: "v1.2.3 is out, and pi is 3.14!",
+		},
+		{
+			name:     "Inline code is untouched",
+			input:    "call `f(a , b)` now",
+			expected: "call `f(a , b)` now",
+		},
+		{
+			name:     "Percent sign attaches by default",
+			input:    "it is 50 % done",
+			expected: "it is 50% done",
+		},
+		{
+			name:     "Escaped modifier next to a URL",
+			input:    `type \(up) at https://example.com now`,
+			expected: "type (up) at https://example.com now",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := processor.ProcessText(tt.input)
+			if result != tt.expected {
+				t.Errorf("Input: %q\nExpected: %q\nGot:      %q", tt.input, tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestTightOperators(t *testing.T) {
+	defer processor.SetOptions(processor.DefaultOptions())
+
+	tests := []struct {
+		operators string
+		input     string
+		expected  string
+	}{
+		{"", "it is 50 % done", "it is 50 % done"},
+		{"&", "rock & roll", "rock& roll"},
+		{processor.AllOperators, "a @ b # c", "a@ b# c"},
+		{processor.AllOperators, "down to -1 now", "down to -1 now"},
+		{processor.AllOperators, "we know 5 - 3 = 2", "we know 5 - 3 = 2"},
+	}
+
+	for _, tt := range tests {
+		opts := processor.DefaultOptions()
+		opts.TightOperators = tt.operators
+		processor.SetOptions(opts)
+		if result := processor.ProcessText(tt.input); result != tt.expected {
+			t.Errorf("Operators %q, input %q\nExpected: %q\nGot:      %q", tt.operators, tt.input, tt.expected, result)
+		}
+	}
+}
+
+func TestPunctuationProfiles(t *testing.T) {
+	defer processor.SetOptions(processor.DefaultOptions())
+
+	tests := []struct {
+		name     string
+		profile  string
+		locale   string
+		input    string
+		expected string
+	}{
+		{
+			name:     "English is unchanged",
+			profile:  "en",
+			input:    `He said " hi " ... really ?`,
+			expected: `He said "hi"... really?`,
+		},
+		{
+			name:     "French spacing before high marks and guillemets",
+			profile:  "fr",
+			input:    `Il a dit " bonjour " ... Vraiment ?! Oui : à 10:30 ; voilà !`,
+			expected: "Il a dit «\u00a0bonjour\u00a0»… Vraiment\u202f?! Oui\u00a0: à 10:30\u202f; voilà\u202f!",
+		},
+		{
+			name:     "French guillemets typed with spaces",
+			profile:  "fr",
+			input:    "« salut »",
+			expected: "«\u00a0salut\u00a0»",
+		},
+		{
+			name:     "German quotes",
+			profile:  "de",
+			input:    `Er sagte " hallo " ... ja !`,
+			expected: "Er sagte „hallo“… ja!",
+		},
+		{
+			name:     "Greek question mark",
+			profile:  "el",
+			input:    `Τι κάνεις ? " καλά "`,
+			expected: "Τι κάνεις; «καλά»",
+		},
+		{
+			name:     "Profile follows the locale",
+			locale:   "de",
+			input:    `" ja "`,
+			expected: "„ja“",
+		},
+		{
+			name:     "URLs keep their question marks",
+			profile:  "el",
+			input:    "see https://example.com/?q=1 now ?",
+			expected: "see https://example.com/?q=1 now;",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := processor.DefaultOptions()
+			opts.PunctuationProfile = tt.profile
+			if tt.locale != "" {
+				opts.Locale = tt.locale
+			}
+			processor.SetOptions(opts)
+			if result := processor.ProcessText(tt.input); result != tt.expected {
+				t.Errorf("Input: %q\nExpected: %q\nGot:      %q", tt.input, tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestPunctuationProfileRoundTrip(t *testing.T) {
+	defer processor.SetOptions(processor.DefaultOptions())
+
+	tests := []struct {
+		name     string
+		profile  string
+		input    string
+		expected string
+	}{
+		{
+			name:     "French output is accepted again",
+			profile:  "fr",
+			input:    `Il a dit " bonjour " ... Vraiment ? Oui : voilà !`,
+			expected: "Il a dit «\u00a0bonjour\u00a0»… Vraiment\u202f? Oui\u00a0: voilà\u202f!",
+		},
+		{
+			name:     "Greek text with a case modifier",
+			profile:  "el",
+			input:    `Τι κάνεις ? " καλά (up) "`,
+			expected: "Τι κάνεις; «ΚΑΛΆ»",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := processor.DefaultOptions()
+			opts.PunctuationProfile = tt.profile
+			processor.SetOptions(opts)
+			result := processor.ProcessText(tt.input)
+			if result != tt.expected {
+				t.Errorf("Input: %q\nExpected: %q\nGot:      %q", tt.input, tt.expected, result)
+			}
+			if again := processor.ProcessText(result); again != result {
+				t.Errorf("Second pass over %q\nExpected: %q\nGot:      %q", result, result, again)
+			}
+		})
+	}
+}
+
+func TestTypography(t *testing.T) {
+	defer processor.SetOptions(processor.DefaultOptions())
+
+	tests := []struct {
+		name     string
+		mode     string
+		spacing  string
+		input    string
+		expected string
+	}{
+		{
+			name:     "Curly double quotes and apostrophes",
+			mode:     processor.TypographySmart,
+			input:    `He said "it's fine" today`,
+			expected: "He said “it’s fine” today",
+		},
+		{
+			name:     "Curly single quotes",
+			mode:     processor.TypographySmart,
+			input:    "the word 'ok' is short",
+			expected: "the word ‘ok’ is short",
+		},
+		{
+			name:     "Elisions and plural possessives are apostrophes",
+			mode:     processor.TypographySmart,
+			input:    "'twas the students' books, 'quoted'",
+			expected: "’twas the students’ books, ‘quoted’",
+		},
+		{
+			name:     "Dashes and ellipsis",
+			mode:     processor.TypographySmart,
+			input:    "pages 10 -- 20 --- or more... maybe",
+			expected: "pages 10 – 20 — or more… maybe",
+		},
+		{
+			name:     "Closed dashes",
+			mode:     processor.TypographySmart,
+			spacing:  processor.DashClosed,
+			input:    "wait --- what",
+			expected: "wait—what",
+		},
+		{
+			name:     "Spaced dashes",
+			mode:     processor.TypographySmart,
+			spacing:  processor.DashSpaced,
+			input:    "wait---what",
+			expected: "wait — what",
+		},
+		{
+			name:     "Command-line flags are not dashes",
+			mode:     processor.TypographySmart,
+			input:    "run it with --verbose now",
+			expected: "run it with --verbose now",
+		},
+		{
+			name:     "Inline code keeps straight quotes",
+			mode:     processor.TypographySmart,
+			input:    "type `echo \"hi\"` here",
+			expected: "type `echo \"hi\"` here",
+		},
+		{
+			name:     "ASCII mode",
+			mode:     processor.TypographyASCII,
+			input:    "He said “it’s fine” – really…",
+			expected: `He said "it's fine" -- really...`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := processor.DefaultOptions()
+			opts.Typography = tt.mode
+			opts.DashSpacing = tt.spacing
+			processor.SetOptions(opts)
+			if result := processor.ProcessText(tt.input); result != tt.expected {
+				t.Errorf("Input: %q\nExpected: %q\nGot:      %q", tt.input, tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestPunctuationRules(t *testing.T) {
+	defer processor.SetOptions(processor.DefaultOptions())
+
+	tests := []struct {
+		name     string
+		rules    string
+		input    string
+		expected string
+	}{
+		{
+			name:     "Canonical abbreviations",
+			rules:    "abbreviations",
+			input:    "fruit, e. g. apples, i.e pears and E. G. plums",
+			expected: "fruit, e.g. apples, i.e. pears and E.G. plums",
+		},
+		{
+			name:     "Abbreviations keep their capitals",
+			rules:    "abbreviations",
+			input:    "at 5 p. m. in the u. s. etc",
+			expected: "at 5 p.m. in the U.S. etc.",
+		},
+		{
+			name:     "Words are not abbreviations",
+			rules:    "abbreviations",
+			input:    "I am with us",
+			expected: "I am with us",
+		},
+		{
+			name:     "Ellipsis character",
+			rules:    "ellipsis=char",
+			input:    "wait . . . and .. . then....",
+			expected: "wait… and… then…",
+		},
+		{
+			name:     "Ellipsis dots",
+			rules:    "ellipsis=dots",
+			input:    "wait… what",
+			expected: "wait... what",
+		},
+		{
+			name:     "Spaced ellipsis",
+			rules:    "ellipsis-spacing=spaced",
+			input:    "wait...what ... (no ...)",
+			expected: "wait ... what ... (no...)",
+		},
+		{
+			name:     "Attached ellipsis",
+			rules:    "ellipsis-spacing=attached",
+			input:    "wait...what ... ok",
+			expected: "wait... what... ok",
+		},
+		{
+			name:     "Interrobang ordering",
+			rules:    "interrobang=?!",
+			input:    "Really!?!? Sure?!",
+			expected: "Really?! Sure?!",
+		},
+		{
+			name:     "Maximum repeated marks",
+			rules:    "max-repeat=2",
+			input:    "No!!!! What???",
+			expected: "No!! What??",
+		},
+		{
+			name:     "Rules are off by default",
+			input:    "e. g. wait . . . Really!?!?",
+			expected: "e. g. wait... Really!?!?",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules, err := processor.ParsePunctuationRules(tt.rules)
+			if err != nil {
+				t.Fatalf("ParsePunctuationRules(%q): %v", tt.rules, err)
+			}
+			opts := processor.DefaultOptions()
+			opts.PunctuationRules = rules
+			processor.SetOptions(opts)
+			if result := processor.ProcessText(tt.input); result != tt.expected {
+				t.Errorf("Input: %q\nExpected: %q\nGot:      %q", tt.input, tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestPunctuationChangesReported(t *testing.T) {
+	defer processor.SetOptions(processor.DefaultOptions())
+
+	opts := processor.DefaultOptions()
+	opts.PunctuationRules, _ = processor.ParsePunctuationRules("abbreviations,interrobang=?!,max-repeat=1")
+	processor.SetOptions(opts)
+
+	result := processor.ProcessTextWithInfo("see e. g. this , ok!!! Why!?!?")
+	for _, want := range []string{
+		"• Punctuation: Abbreviation: 'e. g.' → 'e.g.'",
+		"• Punctuation: Space before punctuation: 'this ,' → 'this,'",
+		"• Punctuation: Repeated marks: '!!!' → '!'",
+		"• Punctuation: Interrobang: '!?!?' → '?!'",
+	} {
+		if !strings.Contains(result, want) {
+			t.Errorf("Expected %q in report:\n%s", want, result)
+		}
+	}
+	if strings.Contains(result, "Applied punctuation formatting") {
+		t.Errorf("Expected individual changes instead of a generic message:\n%s", result)
+	}
+}
+
+func TestParsePunctuationRules(t *testing.T) {
+	for _, list := range []string{"abbrev", "ellipsis=dotty", "max-repeat=0", "abbreviations=yes"} {
+		if _, err := processor.ParsePunctuationRules(list); err == nil {
+			t.Errorf("Expected an error for %q", list)
+		}
+	}
+}
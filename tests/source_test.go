@@ -0,0 +1,199 @@
+// Copyright (c) 2025 Spiros Nikoloudakis
+// Licensed under MIT License - see LICENSE file for details
+
+package tests
+
+import (
+	"go-reloaded/internal/processor"
+	"go-reloaded/internal/source"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSourceLexers(t *testing.T) {
+	tests := []struct {
+		name     string
+		doc      *source.Document
+		expected []string
+	}{
+		{
+			name:     "Go comments and prose strings",
+			doc:      source.ParseGo("package a\n\n// Run starts ,it .\n// Second line\nfunc Run() {\n\ts := \"a // b\" /* c , d */\n\t_ = `json:\"x\"`\n}\n", true),
+			expected: []string{"Run starts ,it .\nSecond line", "a // b", "c , d"},
+		},
+		{
+			name:     "Hash comments skip strings, $# and #! lines",
+			doc:      source.ParseComments("#!/bin/sh\n# one ,\necho \"# two\" '# three' # four\nx=$#\n", source.HashComments),
+			expected: []string{"one ,", "four"},
+		},
+		{
+			name:     "Slash comments skip strings",
+			doc:      source.ParseComments("let a = \"// no\"; // yes\n/*\n * block\n */\nlet b = `/* no */`;\n", source.SlashComments),
+			expected: []string{"yes", "block"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var raw strings.Builder
+			var contents []string
+			for _, b := range tt.doc.Blocks {
+				raw.WriteString(b.Raw)
+				if b.Kind != source.Code {
+					contents = append(contents, b.Content)
+				}
+			}
+			if raw.String() != tt.doc.Text {
+				t.Errorf("Blocks do not add up to the input: %q", raw.String())
+			}
+			if strings.Join(contents, "|") != strings.Join(tt.expected, "|") {
+				t.Errorf("Expected: %q\nGot:      %q", tt.expected, contents)
+			}
+			if mask := tt.doc.Mask(); len(mask) != len(tt.doc.Text) {
+				t.Errorf("Mask changed the length: %q", mask)
+			}
+		})
+	}
+}
+
+func TestSourceMode(t *testing.T) {
+	defer processor.SetOptions(processor.DefaultOptions())
+
+	tests := []struct {
+		name     string
+		format   string
+		strings  bool
+		input    string
+		expected string
+	}{
+		{
+			name:     "Only comments change",
+			format:   processor.FormatGo,
+			input:    "package a\n\n// it is a apple , ok .\nvar x = a , b // a  trailing one .\n",
+			expected: "package a\n\n// it is an apple, ok.\nvar x = a , b // a trailing one.\n",
+		},
+		{
+			name:     "Markers and indentation are kept",
+			format:   processor.FormatGo,
+			input:    "func f() {\n\t/* first ,\n\t * second (up) .\n\t */\n\t// third ,\n\t// fourth .\n}\n",
+			expected: "func f() {\n\t/* first,\n\t * SECOND.\n\t */\n\t// third,\n\t// fourth.\n}\n",
+		},
+		{
+			name:     "CRLF line endings are kept",
+			format:   processor.FormatGo,
+			input:    "package a\r\n\r\n// one ,\r\n// two .\r\n/* three ,\r\n   four . */\r\nvar x int\r\n",
+			expected: "package a\r\n\r\n// one,\r\n// two.\r\n/* three,\r\n   four. */\r\nvar x int\r\n",
+		},
+		{
+			name:     "Directives and code examples are kept",
+			format:   processor.FormatGo,
+			input:    "//go:build linux ,amd64\n\n// use it like this :\n//\n//\tx := f(a ,b)\npackage a //nolint:all ,x\n",
+			expected: "//go:build linux ,amd64\n\n// use it like this:\n//\n//\tx := f(a ,b)\npackage a //nolint:all ,x\n",
+		},
+		{
+			name:     "Strings are kept unless enabled",
+			format:   processor.FormatGo,
+			input:    "package a\n\nvar s = \"hello ,world\"\n",
+			expected: "package a\n\nvar s = \"hello ,world\"\n",
+		},
+		{
+			name:     "Enabled strings keep escapes, verbs and spaces",
+			format:   processor.FormatGo,
+			strings:  true,
+			input:    "package a\n\nimport \"fmt\"\n\nvar s = fmt.Sprintf(\" got %d items ,it is a error .\\n\", n)\n",
+			expected: "package a\n\nimport \"fmt\"\n\nvar s = fmt.Sprintf(\" got %d items,it is an error.\\n\", n)\n",
+		},
+		{
+			name:     "A string that would break is kept",
+			format:   processor.FormatGo,
+			strings:  true,
+			input:    "package a\n\nvar s = `say (quote) hi there`\n",
+			expected: "package a\n\nvar s = `say (quote) hi there`\n",
+		},
+		{
+			name:     "Unterminated and empty strings are kept",
+			format:   processor.FormatGo,
+			strings:  true,
+			input:    "var a = \"\"\nvar b = ``\nvar c = \"open ,string\nvar d = `x ,y",
+			expected: "var a = \"\"\nvar b = ``\nvar c = \"open ,string\nvar d = `x ,y",
+		},
+		{
+			name:     "Hash comments",
+			format:   processor.FormatHash,
+			input:    "#!/usr/bin/env python3\n# compute a average , fast .\nx = \"a ,b\"  # it is a example\n",
+			expected: "#!/usr/bin/env python3\n# compute an average, fast.\nx = \"a ,b\"  # it is an example\n",
+		},
+		{
+			name:     "Slash comments",
+			format:   processor.FormatSlash,
+			input:    "int f(int a ,int b) { // add them (up, 2)\n  return a+b ; /* done . */\n}\n",
+			expected: "int f(int a ,int b) { // ADD THEM\n  return a+b ; /* done. */\n}\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := processor.DefaultOptions()
+			opts.Format = tt.format
+			opts.StringLiterals = tt.strings
+			processor.SetOptions(opts)
+			if result := processor.ProcessText(tt.input); result != tt.expected {
+				t.Errorf("Input: %q\nExpected: %q\nGot:      %q", tt.input, tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestSourceUnterminatedStrings(t *testing.T) {
+	for _, input := range []string{"`x", "``` ", "`code```` x", "\"", "x := \"open"} {
+		doc := source.ParseGo(input, true)
+		var raw strings.Builder
+		for _, b := range doc.Blocks {
+			raw.WriteString(b.Raw)
+		}
+		if raw.String() != input {
+			t.Errorf("Blocks do not add up to %q: %q", input, raw.String())
+		}
+	}
+}
+
+func TestSourceLargeInput(t *testing.T) {
+	inputs := map[string]func(string) *source.Document{
+		strings.Repeat("#\n", 200000): func(text string) *source.Document {
+			return source.ParseComments(text, source.HashComments)
+		},
+		strings.Repeat("x := 1 //\n", 50000): func(text string) *source.Document {
+			return source.ParseGo(text, true)
+		},
+	}
+	for input, parse := range inputs {
+		start := time.Now()
+		doc := parse(input)
+		if elapsed := time.Since(start); elapsed > 2*time.Second {
+			t.Errorf("Parsing %d bytes took %v", len(input), elapsed)
+		}
+		var raw strings.Builder
+		for _, b := range doc.Blocks {
+			raw.WriteString(b.Raw)
+		}
+		if raw.String() != input {
+			t.Errorf("Blocks do not add up to the input")
+		}
+	}
+}
+
+func TestSourceDocName(t *testing.T) {
+	defer processor.SetOptions(processor.DefaultOptions())
+
+	opts := processor.DefaultOptions()
+	opts.Format = processor.FormatGo
+	opts.AutoCapitalize = true
+	processor.SetOptions(opts)
+
+	input := "package a\n\n// parse splits the text. it stops early .\nfunc (p *parser) parse() {}\n\n// options holds settings\ntype options struct{}\n"
+	expected := "package a\n\n// parse splits the text. It stops early.\nfunc (p *parser) parse() {}\n\n// options holds settings\ntype options struct{}\n"
+	if result := processor.ProcessText(input); result != expected {
+		t.Errorf("Expected: %q\nGot:      %q", expected, result)
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright (c) 2025 Spiros Nikoloudakis
+// Licensed under MIT License - see LICENSE file for details
+
+package tests
+
+import (
+	"go-reloaded/internal/processor"
+	"strings"
+	"testing"
+)
+
+func TestTypoFixMode(t *testing.T) {
+	opts := processor.DefaultOptions()
+	opts.Typos = processor.TyposFix
+	opts.RepeatAllowlist = []string{"really"}
+	processor.SetOptions(opts)
+	defer processor.SetOptions(processor.DefaultOptions())
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "Repeated word",
+			input:    "this is is the the answer",
+			expected: "this is the answer",
+		},
+		{
+			name:     "Repeated word keeps the first casing",
+			input:    "The the end",
+			expected: "The end",
+		},
+		{
+			name:     "Run of repeats",
+			input:    "go go go now",
+			expected: "go now",
+		},
+		{
+			name:     "Legitimate repetitions are allowlisted",
+			input:    "he had had enough, I know that that works",
+			expected: "he had had enough, I know that that works",
+		},
+		{
+			name:     "Project allowlist",
+			input:    "it is really really good",
+			expected: "it is really really good",
+		},
+		{
+			name:     "Punctuation between words is not a repeat",
+			input:    "well, well then",
+			expected: "well, well then",
+		},
+		{
+			name:     "Doubled punctuation",
+			input:    "yes,, no;; maybe..",
+			expected: "yes, no; maybe.",
+		},
+		{
+			name:     "Ellipsis is not doubled punctuation",
+			input:    "wait... ok",
+			expected: "wait... ok",
+		},
+		{
+			name:     "Space before apostrophe",
+			input:    "I don 't know, it 's late and we do n't care",
+			expected: "I don't know, it's late and we don't care",
+		},
+		{
+			name:     "Quoted letters are not contractions",
+			input:    "press the 'd' key and the 's' key",
+			expected: "press the 'd' key and the 's' key",
+		},
+		{
+			name:     "Repeat fixed before articles",
+			input:    "eat a a apple",
+			expected: "eat an apple",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := processor.ProcessText(tt.input)
+			if result != tt.expected {
+				t.Errorf("Input: %q\nExpected: %q\nGot:      %q", tt.input, tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestTypoCheckMode(t *testing.T) {
+	opts := processor.DefaultOptions()
+	opts.Typos = processor.TyposCheck
+	processor.SetOptions(opts)
+	defer processor.SetOptions(processor.DefaultOptions())
+
+	input := "the the answer,, I don 't know"
+	result := processor.ProcessTextWithInfo(input)
+	if !strings.HasPrefix(result, "the the answer,, I don 't know") {
+		t.Errorf("Check mode changed the text: %q", result)
+	}
+	for _, want := range []string{
+		"• Typo: Repeated word: 'the the' (check only, left unchanged)",
+		"• Typo: Doubled punctuation: ',,' (check only, left unchanged)",
+		"• Typo: Split contraction: 'don 't' (check only, left unchanged)",
+	} {
+		if !strings.Contains(result, want) {
+			t.Errorf("Report missing %q:\n%s", want, result)
+		}
+	}
+
+	diagnostics := processor.Diagnose(input)
+	if len(diagnostics) != 3 || diagnostics[0].String() != "1:1: warning: repeated word 'the the' - did you mean 'the'?" {
+		t.Errorf("Unexpected diagnostics: %v", diagnostics)
+	}
+}
+
+func TestTypoRuleIsOptIn(t *testing.T) {
+	input := "the the answer,, I don 't know"
+	if result := processor.ProcessText(input); !strings.Contains(result, "the the") {
+		t.Errorf("Typo rule ran without being enabled: %q", result)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"go-reloaded/internal/validator"
 	"strings"
 	"testing"
+	"unicode/utf8"
 )
 
 func TestValidationProtection(t *testing.T) {
@@ -92,6 +93,18 @@ func TestValidationProtection(t *testing.T) {
 			wantErr: true,
 			errType: "NON_KEYBOARD_CHARACTER",
 		},
+		{
+			name:    "Greek text before a rejected character",
+			input:   "Καλημέρα 日本",
+			wantErr: true,
+			errType: "NON_KEYBOARD_CHARACTER",
+		},
+		{
+			name:    "Typographic quotes before a rejected character",
+			input:   "he said ’ok’ — fine… 日本",
+			wantErr: true,
+			errType: "NON_KEYBOARD_CHARACTER",
+		},
 		{
 			name:    "Valid keyboard characters",
 			input:   "Hello! @#$%^&*()_+-={}[]|\\:;<>?,./ 123",
@@ -125,6 +138,32 @@ func TestValidationProtection(t *testing.T) {
 	}
 }
 
+func TestNonKeyboardCharacterPosition(t *testing.T) {
+	err := validator.ValidateInput("Καλημέρα 日本")
+	validationErr, ok := err.(validator.ValidationError)
+	if !ok {
+		t.Fatalf("Expected ValidationError, got %T", err)
+	}
+	if validationErr.Position != 9 {
+		t.Errorf("Expected rune position 9, got %d", validationErr.Position)
+	}
+	if !strings.Contains(validationErr.Context, ">>>日<<<") {
+		t.Errorf("Expected highlighted character in context, got %q", validationErr.Context)
+	}
+}
+
+func TestVerbatimRegionKeepsRunePositions(t *testing.T) {
+	input := "(go-reloaded:off) Καλημέρα (go-reloaded:on) hello (up world"
+	err := validator.ValidateInput(input)
+	validationErr, ok := err.(validator.ValidationError)
+	if !ok {
+		t.Fatalf("Expected ValidationError, got %T", err)
+	}
+	if expected := utf8.RuneCountInString(input[:strings.Index(input, "(up")]); validationErr.Position != expected {
+		t.Errorf("Expected rune position %d, got %d", expected, validationErr.Position)
+	}
+}
+
 func TestProcessorWithValidation(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -263,4 +302,63 @@ func TestSafeIndexing(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+
+func TestCaseBlockValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{
+			name:    "Balanced block",
+			input:   "(up:start) hello (up:end)",
+			wantErr: false,
+		},
+		{
+			name:    "Nested blocks",
+			input:   "(up:start) a (low:start) b (low:end) (up:end)",
+			wantErr: false,
+		},
+		{
+			name:    "Missing end",
+			input:   "(up:start) hello",
+			wantErr: true,
+		},
+		{
+			name:    "Missing start",
+			input:   "hello (up:end)",
+			wantErr: true,
+		},
+		{
+			name:    "Crossed blocks",
+			input:   "(up:start) a (low:start) b (up:end) (low:end)",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.ValidateInput(tt.input)
+			if !tt.wantErr {
+				if err != nil {
+					t.Errorf("Expected no error but got: %v", err)
+				}
+				return
+			}
+			validationErr, ok := err.(validator.ValidationError)
+			if !ok || validationErr.Type != "UNBALANCED_BLOCK" {
+				t.Errorf("Expected UNBALANCED_BLOCK error, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestEscapedModifiersNotCounted(t *testing.T) {
+	for _, tag := range []string{"\\(up)", "((up))"} {
+		input := strings.Repeat("literal "+tag+" ", 1001)
+		if err := validator.ValidateInput(input); err != nil {
+			t.Errorf("Escaped modifiers %s should not count as transformations, got: %v", tag, err)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright (c) 2025 Spiros Nikoloudakis
+// Licensed under MIT License - see LICENSE file for details
+
+package tests
+
+import (
+	"go-reloaded/internal/processor"
+	"strings"
+	"testing"
+)
+
+func TestWrap(t *testing.T) {
+	defer processor.SetOptions(processor.DefaultOptions())
+
+	tests := []struct {
+		name     string
+		width    int
+		justify  bool
+		input    string
+		expected string
+	}{
+		{
+			name:     "Paragraphs reflow separately",
+			width:    20,
+			input:    "the quick brown fox jumps over the lazy dog\n\nshort one",
+			expected: "the quick brown fox\njumps over the lazy\ndog\n\nshort one",
+		},
+		{
+			name:     "Short lines are joined",
+			width:    30,
+			input:    "one\ntwo\nthree",
+			expected: "one two three",
+		},
+		{
+			name:     "List markers hang",
+			width:    20,
+			input:    "- first item that is rather long\n- second\n1. numbered item goes on and on",
+			expected: "- first item that is\n  rather long\n- second\n1. numbered item\n   goes on and on",
+		},
+		{
+			name:     "Quote prefixes repeat",
+			width:    20,
+			input:    "> a quoted line that needs to be wrapped",
+			expected: "> a quoted line that\n> needs to be\n> wrapped",
+		},
+		{
+			name:     "URLs are never broken",
+			width:    20,
+			input:    "see https://example.com/a/very/long/path for more",
+			expected: "see\nhttps://example.com/a/very/long/path\nfor more",
+		},
+		{
+			name:     "Justified lines",
+			width:    20,
+			justify:  true,
+			input:    "the quick brown fox jumps over the lazy dog",
+			expected: "the  quick brown fox\njumps  over the lazy\ndog",
+		},
+		{
+			name:     "Tag sets the width of its paragraph",
+			input:    "the quick brown fox jumps over the lazy dog (wrap, 20)\n\nthe quick brown fox jumps over the lazy dog",
+			expected: "the quick brown fox\njumps over the lazy\ndog\n\nthe quick brown fox jumps over the lazy dog",
+		},
+		{
+			name:     "Tag overrides the option",
+			width:    72,
+			input:    "(wrap, 10)\nthe quick brown fox",
+			expected: "the quick\nbrown fox",
+		},
+		{
+			name:     "Zero width is raised to the minimum",
+			input:    "the quick brown fox (wrap, 0)",
+			expected: "the quick\nbrown fox",
+		},
+		{
+			name:     "Modifiers apply before wrapping",
+			width:    12,
+			input:    "make this loud (up, 3) please",
+			expected: "MAKE THIS\nLOUD please",
+		},
+		{
+			name:     "Without a width plain text is flattened as before",
+			input:    "one\ntwo",
+			expected: "one two",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := processor.DefaultOptions()
+			opts.Wrap = tt.width
+			opts.Justify = tt.justify
+			processor.SetOptions(opts)
+			if result := processor.ProcessText(tt.input); result != tt.expected {
+				t.Errorf("Input: %q\nExpected: %q\nGot:      %q", tt.input, tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestWrapDisplayWidth(t *testing.T) {
+	defer processor.SetOptions(processor.DefaultOptions())
+
+	opts := processor.DefaultOptions()
+	opts.Wrap = 14
+	processor.SetOptions(opts)
+	// Wide characters take two columns and combining accents none
+	input := "日本語 日本語 日本語 cafe\u0301 cafe\u0301"
+	expected := "日本語 日本語\n日本語 cafe\u0301\ncafe\u0301"
+	if result := processor.ProcessTextUnsafe(input); result != expected {
+		t.Errorf("Input: %q\nExpected: %q\nGot:      %q", input, expected, result)
+	}
+}
+
+func TestWrapMeasuresProtectedTokens(t *testing.T) {
+	defer processor.SetOptions(processor.DefaultOptions())
+
+	opts := processor.DefaultOptions()
+	opts.Wrap = 30
+	processor.SetOptions(opts)
+	input := "mail someone@example.com or visit https://example.com today"
+	for _, line := range strings.Split(processor.ProcessText(input), "\n") {
+		if len(line) > 30 && !strings.Contains(line, "https://") {
+			t.Errorf("Line %q is wider than 30 columns", line)
+		}
+	}
+	if result := processor.ProcessText(input); !strings.Contains(result, "someone@example.com") || !strings.Contains(result, "https://example.com") {
+		t.Errorf("Protected tokens were changed: %q", result)
+	}
+}
+
+func TestWrapDisabledByDirective(t *testing.T) {
+	defer processor.SetOptions(processor.DefaultOptions())
+
+	opts := processor.DefaultOptions()
+	opts.Wrap = 10
+	processor.SetOptions(opts)
+	input := "(go-reloaded:disable wrap) keep this line as it is"
+	if result := processor.ProcessText(input); strings.Contains(result, "\n") {
+		t.Errorf("Expected no wrapping, got %q", result)
+	}
+}